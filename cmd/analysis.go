@@ -0,0 +1,370 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/breaking"
+	"github.com/rshdhere/vibecheck/internal/classify"
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/conventional"
+	"github.com/rshdhere/vibecheck/internal/deps"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/ignore"
+	"github.com/rshdhere/vibecheck/internal/issue"
+	"github.com/rshdhere/vibecheck/internal/patch"
+	"github.com/rshdhere/vibecheck/internal/prompt"
+	"github.com/rshdhere/vibecheck/internal/scope"
+	"github.com/rshdhere/vibecheck/internal/structure"
+)
+
+// analysis carries what vibecheck works out locally about the staged change
+// before a provider is asked for a message
+type analysis struct {
+	// diff is the staged diff as sent to the provider, with ignored files
+	// replaced by one-line summaries
+	diff     string
+	excluded []string
+	// statOnly is set when diff is a diffstat rather than a patch
+	statOnly bool
+	// sent is diff as the provider received it, after redaction
+	sent string
+
+	files          []string
+	allowedScopes  []string
+	dominantScopes []string
+
+	branch         string
+	issueKey       string
+	issuePlacement issue.Placement
+	issueFooter    string
+
+	breaking []breaking.Finding
+	deps     []deps.Change
+	symbols  []structure.Change
+
+	// localMessage is set when the change is described exactly without a provider
+	localMessage string
+}
+
+// diffSource tells the analysis where the two sides of a diff can be read
+type diffSource struct {
+	// before and after are revisions for git.FileAt, after "" being the index
+	before, after string
+	// detached diffs, such as patches from email, have no sides to read
+	detached bool
+	// ignoringWhitespace produces the same diff with whitespace ignored, when
+	// that is possible
+	ignoringWhitespace func(context.Context) (string, error)
+}
+
+// stagedSource reads the staged changes selected by pathspecs, compared with
+// base, or with HEAD when base is empty
+func stagedSource(base string, pathspecs []string) diffSource {
+	before := base
+	if before == "" {
+		before = "HEAD"
+	}
+	return diffSource{
+		before: before,
+		ignoringWhitespace: func(ctx context.Context) (string, error) {
+			return git.StagedDiffSince(ctx, base, git.DiffOptions{IgnoreWhitespace: true}, pathspecs...)
+		},
+	}
+}
+
+// revisionSource reads a range such as main..topic or main...topic, or a
+// single commit against its parent
+func revisionSource(ctx context.Context, rev string) (diffSource, error) {
+	src := diffSource{
+		ignoringWhitespace: func(ctx context.Context) (string, error) {
+			return git.RevisionDiff(ctx, git.DiffOptions{IgnoreWhitespace: true}, rev)
+		},
+	}
+	orHead := func(r string) string {
+		if r == "" {
+			return "HEAD"
+		}
+		return r
+	}
+	if from, to, ok := strings.Cut(rev, "..."); ok {
+		base, err := git.MergeBase(ctx, orHead(from), orHead(to))
+		if err != nil {
+			return src, err
+		}
+		src.before, src.after = base, orHead(to)
+	} else if from, to, ok := strings.Cut(rev, ".."); ok {
+		src.before, src.after = orHead(from), orHead(to)
+	} else {
+		parent, err := git.Parent(ctx, rev)
+		if err != nil {
+			return src, err
+		}
+		src.before, src.after = parent, rev
+	}
+	return src, nil
+}
+
+// analyzeStaged inspects the staged diff and the files selected by pathspecs.
+// issueKey, when given, takes precedence over the key found in the branch name.
+func analyzeStaged(ctx context.Context, cfg *config.Resolved, diff string, pathspecs []string, issueKey string) (*analysis, error) {
+	return analyzeDiff(ctx, cfg, diff, stagedSource("", pathspecs), issueKey)
+}
+
+// analyzeDiff inspects diff, reading the full files on both sides from src
+func analyzeDiff(ctx context.Context, cfg *config.Resolved, diff string, src diffSource, issueKey string) (*analysis, error) {
+	// Checked before any tokens are spent on a message it would then reject
+	placement, err := issue.ParsePlacement(cfg.Issue.Placement)
+	if err != nil {
+		return nil, fmt.Errorf("issue.placement: %w", err)
+	}
+	ignored, err := ignore.Load(cfg.RepoRoot, cfg.Ignore)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", ignore.FileName, err)
+	}
+	sections := patch.Parse(diff)
+	var files []string
+	for _, f := range sections {
+		if !ignored.Match(f.Path) {
+			files = append(files, f.Path)
+		}
+	}
+	depChanges, depsOnly, err := dependencyChanges(ctx, sections, src)
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := structuralChanges(ctx, sections, src, ignored)
+	if err != nil {
+		return nil, err
+	}
+	diff, excluded := excludeIgnored(diff, ignored)
+
+	rules := scope.FromMap(cfg.ScopeMap)
+	if cfg.Codeowners && cfg.RepoRoot != "" {
+		owners, err := scope.LoadCodeowners(cfg.RepoRoot)
+		if err != nil {
+			return nil, fmt.Errorf("read CODEOWNERS: %w", err)
+		}
+		rules = append(rules, owners...)
+	}
+
+	a := &analysis{
+		diff:           diff,
+		excluded:       excluded,
+		files:          files,
+		allowedScopes:  scope.Allowed(rules, cfg.Scopes),
+		dominantScopes: scope.Dominant(scope.Infer(files, rules)),
+		issueKey:       issueKey,
+		issuePlacement: placement,
+		issueFooter:    cfg.Issue.Footer,
+		breaking:       breaking.Detect(diff),
+		deps:           depChanges,
+		symbols:        symbols,
+	}
+	if !cfg.ForceLLM {
+		if a.localMessage, err = localMessage(ctx, sections, depsOnly, depChanges, src); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.issueKey == "" && len(cfg.Issue.Patterns) > 0 {
+		a.branch, err = git.CurrentBranch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("current branch: %w", err)
+		}
+		a.issueKey, err = issue.Extract(a.branch, cfg.Issue.Patterns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// requireIssue enforces issue.required for the commands that write a commit
+// message, which is what needs the key; descriptions and fixups do not
+func (a *analysis) requireIssue(cfg *config.Resolved) error {
+	if a.issueKey == "" && cfg.Issue.Required {
+		return fmt.Errorf("%w: branch %q has no issue key matching %s (pass --%s to set one)",
+			errIssueRequired, a.branch, strings.Join(cfg.Issue.Patterns, ", "), issueFlagName)
+	}
+	return nil
+}
+
+// excludeIgnored replaces the sections of ignored files with a one-line summary
+// such as "go.sum: 40 lines changed" and returns the paths it left out
+func excludeIgnored(diff string, ignored *ignore.Matcher) (string, []string) {
+	if ignored.Empty() {
+		return diff, nil
+	}
+	var b strings.Builder
+	var excluded []string
+	for _, f := range patch.Parse(diff) {
+		if ignored.Match(f.Path) {
+			b.WriteString(f.Summary() + "\n")
+			excluded = append(excluded, f.Path)
+			continue
+		}
+		b.WriteString(f.Text)
+	}
+	return b.String(), excluded
+}
+
+// localMessage describes changes that need no provider: dependency-only
+// changes, and the trivial classes recognized by the classify package. It
+// returns "" for everything else.
+func localMessage(ctx context.Context, sections []patch.File, depsOnly bool, depChanges []deps.Change, src diffSource) (string, error) {
+	if depsOnly {
+		return deps.Message(depChanges), nil
+	}
+	// Without a whitespace-insensitive diff no file counts as whitespace-only
+	ws := sections
+	if src.ignoringWhitespace != nil {
+		diff, err := src.ignoringWhitespace(ctx)
+		if err != nil {
+			return "", fmt.Errorf("changes ignoring whitespace: %w", err)
+		}
+		ws = patch.Parse(diff)
+	}
+	return classify.Classify(sections, ws).Message, nil
+}
+
+// sides reads the versions of f before and after the change
+func (src diffSource) sides(ctx context.Context, f patch.File) (string, string, error) {
+	oldPath := f.Path
+	if f.OldPath != "" {
+		oldPath = f.OldPath
+	}
+	before, after := "", ""
+	var err error
+	if !f.New {
+		if before, _, err = git.FileAt(ctx, src.before, oldPath); err != nil {
+			return "", "", err
+		}
+	}
+	if !f.Removed {
+		if after, _, err = git.FileAt(ctx, src.after, f.Path); err != nil {
+			return "", "", err
+		}
+	}
+	return before, after, nil
+}
+
+// dependencyChanges reads the changed manifests and reports how their
+// dependencies changed, and whether the diff consists of nothing else
+func dependencyChanges(ctx context.Context, files []patch.File, src diffSource) ([]deps.Change, bool, error) {
+	if src.detached {
+		return nil, false, nil
+	}
+	var changes []deps.Change
+	depsOnly := len(files) > 0
+	for _, f := range files {
+		switch {
+		case deps.IsManifest(f.Path):
+			before, after, err := src.sides(ctx, f)
+			if err != nil {
+				return nil, false, err
+			}
+			found, err := deps.Diff(f.Path, before, after)
+			if err != nil {
+				// A manifest that does not parse is left to the provider
+				depsOnly = false
+				continue
+			}
+			if len(found) == 0 || !deps.Explains(found, f.Text) {
+				depsOnly = false
+			}
+			changes = append(changes, found...)
+		case deps.IsLockfile(f.Path):
+		default:
+			depsOnly = false
+		}
+	}
+	return changes, depsOnly && len(changes) > 0, nil
+}
+
+// structuralChanges compares both versions of every file a structure parser is
+// registered for. Files that do not parse, typically work in progress, are
+// left to the raw diff.
+func structuralChanges(ctx context.Context, files []patch.File, src diffSource, ignored *ignore.Matcher) ([]structure.Change, error) {
+	if src.detached {
+		return nil, nil
+	}
+	var changes []structure.Change
+	for _, f := range files {
+		if f.Binary || ignored.Match(f.Path) || !structure.Supported(f.Path) {
+			continue
+		}
+		before, after, err := src.sides(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		found, err := structure.Compare(f.Path, before, after)
+		if err != nil {
+			continue
+		}
+		changes = append(changes, found...)
+	}
+	return changes, nil
+}
+
+// errIssueRequired is returned when repository policy demands an issue key and none was found
+var errIssueRequired = errors.New("issue key required")
+
+// addPromptSections hands the locally derived facts to the provider
+func (a *analysis) addPromptSections(b *prompt.Builder) {
+	if len(a.allowedScopes) > 0 {
+		b.Add("Allowed scopes", fmt.Sprintf("Use only one of these scopes: %s", strings.Join(a.allowedScopes, ", ")))
+	}
+	if len(a.dominantScopes) > 0 {
+		b.Add("Suggested scope", fmt.Sprintf("Based on the staged paths the scope should be: %s", strings.Join(a.dominantScopes, " or ")))
+	}
+	a.addChangeSections(b)
+	if len(a.breaking) > 0 {
+		b.Add("Possible breaking changes", a.breakingList()+"\nMention these in the body. Do not add \"!\" or a BREAKING CHANGE footer, they are added after the user confirms.")
+	}
+}
+
+// addChangeSections adds the facts about the diff itself, which hold for any
+// text written from it and not just commit messages
+func (a *analysis) addChangeSections(b *prompt.Builder) {
+	if a.statOnly {
+		b.Add("Diff format", "Only a diffstat is shared, not the code. Describe the change from the file names, line counts and the facts below.")
+	}
+	if len(a.deps) > 0 {
+		lines := make([]string, 0, len(a.deps)+1)
+		lines = append(lines, "Read from the manifests, use these names and versions exactly:")
+		for _, c := range a.deps {
+			lines = append(lines, "- "+c.String())
+		}
+		b.Add("Dependency changes", strings.Join(lines, "\n"))
+	}
+	if len(a.symbols) > 0 {
+		b.Add("Changed symbols", "Parsed from the old and new sources, name these in the scope and summary where they fit:\n"+structure.Summary(a.symbols))
+	}
+}
+
+// breakingList lists the possible breaking changes, one per line
+func (a *analysis) breakingList() string {
+	lines := make([]string, 0, len(a.breaking))
+	for _, f := range a.breaking {
+		lines = append(lines, "- "+f.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// finalize enforces the local analysis on the generated message
+func (a *analysis) finalize(message string) (string, error) {
+	// Locally written messages already use the conventional scope, e.g. deps
+	if a.localMessage == "" {
+		message = conventional.RewriteHeader(message, func(h *conventional.Header) {
+			h.Scope, _ = scope.Validate(h.Scope, a.allowedScopes, a.dominantScopes)
+		})
+	}
+	return issue.Apply(message, a.issueKey, a.issuePlacement)
+}
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/fixup"
+	"github.com/rshdhere/vibecheck/internal/ignore"
+	"github.com/rshdhere/vibecheck/internal/structure"
+)
+
+func TestAnalysisFinalize(t *testing.T) {
+	a := &analysis{allowedScopes: []string{"cli", "llm"}, dominantScopes: []string{"cli"}}
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"feat(commands): add flag\n- detail", "feat(cli): add flag\n- detail"},
+		{"fix(llm): handle timeout", "fix(llm): handle timeout"},
+		{"chore: tidy", "chore: tidy"},
+		{"Not a conventional header", "Not a conventional header"},
+	}
+	for _, tt := range tests {
+		got, err := a.finalize(tt.message)
+		if err != nil {
+			t.Fatalf("finalize(%q) error = %v", tt.message, err)
+		}
+		if got != tt.want {
+			t.Errorf("finalize(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestAnalysisFinalizeIssueKey(t *testing.T) {
+	a := &analysis{issueKey: "PAY-1234", issuePlacement: "scope"}
+
+	got, err := a.finalize("feat(api): add refund flow")
+	if err != nil {
+		t.Fatalf("finalize() error = %v", err)
+	}
+	if want := "feat(PAY-1234): add refund flow"; got != want {
+		t.Errorf("finalize() = %q, want %q", got, want)
+	}
+
+	// Without a Conventional Commit header there is no scope to put it in
+	got, err = a.finalize("Add refund flow")
+	if err != nil {
+		t.Fatalf("finalize() error = %v", err)
+	}
+	if want := "PAY-1234 Add refund flow"; got != want {
+		t.Errorf("finalize() = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyzeStagedIssuePolicy(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	for _, args := range [][]string{{"init", "-q"}, {"checkout", "-q", "-b", "feature/PAY-1234-refund-flow"}} {
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatalf("git %v error = %v", args, err)
+		}
+	}
+
+	cfg := &config.Resolved{Config: &config.Config{Issue: config.IssueConfig{
+		Patterns: []string{`[A-Z]+-\d+`},
+		Required: true,
+	}}}
+
+	a, err := analyzeStaged(context.Background(), cfg, "", nil, "")
+	if err != nil {
+		t.Fatalf("analyzeStaged() error = %v", err)
+	}
+	if a.issueKey != "PAY-1234" {
+		t.Errorf("issueKey = %q, want PAY-1234", a.issueKey)
+	}
+
+	if err := exec.Command("git", "checkout", "-q", "-b", "no-ticket").Run(); err != nil {
+		t.Fatalf("git checkout error = %v", err)
+	}
+	a, err = analyzeStaged(context.Background(), cfg, "", nil, "")
+	if err != nil {
+		t.Fatalf("analyzeStaged() without a key error = %v", err)
+	}
+	if err := a.requireIssue(cfg); !errors.Is(err, errIssueRequired) {
+		t.Errorf("requireIssue() error = %v, want errIssueRequired", err)
+	}
+
+	a, err = analyzeStaged(context.Background(), cfg, "", nil, "OPS-7")
+	if err != nil {
+		t.Fatalf("analyzeStaged() with explicit key error = %v", err)
+	}
+	if a.issueKey != "OPS-7" {
+		t.Errorf("issueKey = %q, want OPS-7", a.issueKey)
+	}
+
+	cfg.Issue.Placement = "sideways"
+	if _, err := analyzeStaged(context.Background(), cfg, "", nil, "OPS-7"); err == nil || !strings.Contains(err.Error(), "issue.placement") {
+		t.Errorf("analyzeStaged() with an unknown placement error = %v", err)
+	}
+}
+
+func TestIssueRequiredLeavesDescriptionsAndFixups(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+	t.Setenv("HOME", t.TempDir())
+
+	var reply string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"response": reply})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "upstream")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	os.WriteFile(".vibecheck.yaml", []byte("default_provider: ollama\nissue:\n  patterns: ['[A-Z]+-\\d+']\n  required: true\n"), 0644)
+	os.WriteFile("a.txt", []byte("one\n"), 0644)
+	run("add", ".")
+	run("commit", "-q", "-m", "chore: init")
+	run("checkout", "-q", "-b", "no-ticket")
+	os.WriteFile("a.txt", []byte("one\ntwo\n"), 0644)
+	run("commit", "-q", "-am", "feat: add two")
+
+	reply = "Add two\n\n## Summary\nAdds two."
+	var out bytes.Buffer
+	prCmd.SetContext(context.Background())
+	prCmd.SetOut(&out)
+	defer prCmd.SetOut(nil)
+	if err := prCmd.Flags().Set(baseFlagName, "upstream"); err != nil {
+		t.Fatalf("set base flag error = %v", err)
+	}
+	if err := prCmd.RunE(prCmd, nil); err != nil {
+		t.Fatalf("pr with issue.required error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "Add two\n") {
+		t.Errorf("pr output = %q", out.String())
+	}
+
+	cfg, err := resolveConfig(prCmd)
+	if err != nil {
+		t.Fatalf("resolveConfig() error = %v", err)
+	}
+	reply = "beef2222"
+	candidates := []fixup.Candidate{{Hash: "abcd1111", Subject: "chore: init"}, {Hash: "beef2222", Subject: "feat: add two"}}
+	got, err := breakFixupTie(prCmd, cfg, "diff --git a/a.txt b/a.txt\n", candidates, "")
+	if err != nil {
+		t.Fatalf("breakFixupTie() with issue.required error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("breakFixupTie() = %d, want 1", got)
+	}
+}
+
+func TestExcludeIgnored(t *testing.T) {
+	diff := "diff --git a/go.sum b/go.sum\n--- a/go.sum\n+++ b/go.sum\n@@ -1,2 +1,2 @@\n-a v1 h1:x=\n+a v2 h1:y=\n" +
+		"diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-package old\n+package main\n"
+
+	m, err := ignore.New([]string{"go.sum"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, excluded := excludeIgnored(diff, m)
+	want := "go.sum: 2 lines changed\n" +
+		"diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-package old\n+package main\n"
+	if got != want {
+		t.Errorf("excludeIgnored() = %q, want %q", got, want)
+	}
+	if len(excluded) != 1 || excluded[0] != "go.sum" {
+		t.Errorf("excluded = %v, want [go.sum]", excluded)
+	}
+
+	if got, _ := excludeIgnored(diff, &ignore.Matcher{}); got != diff {
+		t.Error("excludeIgnored() changed the diff without patterns")
+	}
+}
+
+func TestAnalyzeStagedDependencyOnly(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	git := func(args ...string) {
+		t.Helper()
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatalf("git %v error = %v", args, err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "user.name", "Jane Doe")
+	git("config", "user.email", "jane@example.com")
+	write("go.mod", "module x\n\nrequire github.com/spf13/cobra v1.8.0\n")
+	write("go.sum", "github.com/spf13/cobra v1.8.0 h1:a=\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	write("go.mod", "module x\n\nrequire github.com/spf13/cobra v1.9.1\n")
+	write("go.sum", "github.com/spf13/cobra v1.9.1 h1:b=\n")
+	git("add", ".")
+
+	stagedDiff := func() string {
+		out, err := exec.Command("git", "diff", "--staged").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	cfg := &config.Resolved{Config: &config.Config{}}
+	a, err := analyzeStaged(context.Background(), cfg, stagedDiff(), nil, "")
+	if err != nil {
+		t.Fatalf("analyzeStaged() error = %v", err)
+	}
+	if want := "chore(deps): bump github.com/spf13/cobra from v1.8.0 to v1.9.1"; a.localMessage != want {
+		t.Errorf("localMessage = %q, want %q", a.localMessage, want)
+	}
+
+	// Any other change hands the message back to the provider, with the facts
+	write("main.go", "package main\n\nfunc main() {}\n")
+	git("add", "main.go")
+	a, err = analyzeStaged(context.Background(), cfg, stagedDiff(), nil, "")
+	if err != nil {
+		t.Fatalf("analyzeStaged() error = %v", err)
+	}
+	if a.localMessage != "" || len(a.deps) != 1 {
+		t.Errorf("localMessage = %q, deps = %v", a.localMessage, a.deps)
+	}
+	if got := structure.Summary(a.symbols); got != "- main.go: added func main" {
+		t.Errorf("symbols = %q", got)
+	}
+}
+
+func TestAnalyzeStagedTrivialChanges(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	git := func(args ...string) {
+		t.Helper()
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatalf("git %v error = %v", args, err)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "user.name", "Jane Doe")
+	git("config", "user.email", "jane@example.com")
+	if err := os.WriteFile("util.go", []byte("package main\n\nfunc add(a, b int) int { return a + b }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+	git("mv", "util.go", "math.go")
+
+	out, err := exec.Command("git", "diff", "--staged", "-M").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Resolved{Config: &config.Config{}}
+	a, err := analyzeStaged(context.Background(), cfg, string(out), nil, "")
+	if err != nil {
+		t.Fatalf("analyzeStaged() error = %v", err)
+	}
+	if want := "refactor: rename util.go to math.go"; a.localMessage != want {
+		t.Errorf("localMessage = %q, want %q", a.localMessage, want)
+	}
+
+	cfg.ForceLLM = true
+	a, err = analyzeStaged(context.Background(), cfg, string(out), nil, "")
+	if err != nil {
+		t.Fatalf("analyzeStaged() error = %v", err)
+	}
+	if a.localMessage != "" {
+		t.Errorf("localMessage with force_llm = %q, want empty", a.localMessage)
+	}
+}
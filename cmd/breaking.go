@@ -0,0 +1,73 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/breaking"
+	"github.com/rshdhere/vibecheck/internal/conventional"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const breakingFlagName = "breaking"
+
+// isTerminal reports whether f is attached to an interactive terminal
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// confirmBreaking decides whether the commit is marked as breaking. An explicit
+// --breaking or --breaking=false wins; otherwise the user is asked about the
+// detected findings when running interactively.
+func confirmBreaking(cmd *cobra.Command, findings []breaking.Finding) (bool, error) {
+	if cmd.Flags().Changed(breakingFlagName) {
+		return cmd.Flags().GetBool(breakingFlagName)
+	}
+	if len(findings) == 0 {
+		return false, nil
+	}
+
+	out := cmd.ErrOrStderr()
+	fmt.Fprintln(out, "Possible breaking changes:")
+	for _, f := range findings {
+		fmt.Fprintf(out, "  - %s\n", f)
+	}
+	if !interactive(cmd) {
+		fmt.Fprintf(out, "Not marked as breaking; pass --%s to mark it.\n", breakingFlagName)
+		return false, nil
+	}
+	return askYesNo(cmd.InOrStdin(), out, "Mark this commit as a breaking change?")
+}
+
+// askYesNo asks question and reads the answer; anything but y or yes is a no
+func askYesNo(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
+
+// markBreaking adds "!" and a BREAKING CHANGE footer describing the findings.
+// Without findings the header description is reused.
+func markBreaking(message string, findings []breaking.Finding) string {
+	description := breaking.Summary(findings)
+	if description == "" {
+		header, _ := conventional.SplitMessage(message)
+		description = header
+		if h, ok := conventional.ParseHeader(header); ok {
+			description = h.Description
+		}
+	}
+	return conventional.MarkBreaking(message, description)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/breaking"
+)
+
+func TestAskYesNo(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got, err := askYesNo(strings.NewReader(tt.input), &out, "Continue?")
+		if err != nil {
+			t.Fatalf("askYesNo(%q) error = %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("askYesNo(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if out.String() != "Continue? [y/N] " {
+			t.Errorf("askYesNo() prompt = %q", out.String())
+		}
+	}
+}
+
+func TestMarkBreaking(t *testing.T) {
+	findings := []breaking.Finding{{Kind: breaking.KindRemoved, Name: "GetRegisteredNames"}}
+	got := markBreaking("refactor(llm): simplify registry", findings)
+	want := "refactor(llm)!: simplify registry\n\nBREAKING CHANGE: removes or renames GetRegisteredNames"
+	if got != want {
+		t.Errorf("markBreaking() = %q, want %q", got, want)
+	}
+
+	got = markBreaking("feat: drop v1 api", nil)
+	if want := "feat!: drop v1 api\n\nBREAKING CHANGE: drop v1 api"; got != want {
+		t.Errorf("markBreaking() without findings = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,305 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/rshdhere/vibecheck/internal/changelog"
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	summaryFlagName = "summary"
+	prependFlagName = "prepend"
+
+	outputMarkdown = "markdown"
+
+	// defaultChangelog is the file --prepend writes to without a value
+	defaultChangelog = "CHANGELOG.md"
+)
+
+// summaryPrompt replaces the commit message instructions of the providers
+const summaryPrompt = `You are a senior software engineer writing release notes.
+Given the changelog entries of one release, write a single short paragraph of two to four sentences that tells users what the release brings and what they must watch out for, leading with breaking changes.
+Write plain prose: no headings, lists, Markdown or commit hashes, and do not invent changes that are not listed.
+The user's extra context is in the next message and the changelog entries in the one after.`
+
+// releaseRange is the commits of one release: those reachable from to but
+// not from from, all of history up to to when from is empty
+type releaseRange struct {
+	version string
+	date    string
+	from    string
+	to      string
+}
+
+// planChangelog splits a <from>..<to> range into one release per tag, newest
+// first. Commits after the newest tag form the Unreleased release.
+func planChangelog(ctx context.Context, from, to string) ([]releaseRange, error) {
+	toCommit, err := git.RevParse(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := git.Tags(ctx, to, from)
+	if err != nil {
+		return nil, err
+	}
+	// Tag dates can tie or lie; the history decides which release came first
+	rev := to
+	if from != "" {
+		rev = from + ".." + to
+	}
+	commits, err := git.Log(ctx, "--topo-order", rev)
+	if err != nil {
+		return nil, err
+	}
+	position := make(map[string]int, len(commits))
+	for i, c := range commits {
+		position[c.Hash] = i
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return position[tags[i].Commit] < position[tags[j].Commit] })
+
+	var ranges []releaseRange
+	current, currentCommit := releaseRange{version: changelog.Unreleased, to: to}, toCommit
+	for _, t := range tags {
+		// Several tags on one commit make one release, named after the newest
+		if t.Commit == currentCommit {
+			if current.version == changelog.Unreleased {
+				current.version, current.date = t.Name, t.Date
+			}
+			continue
+		}
+		current.from = t.Name
+		ranges = append(ranges, current)
+		current, currentCommit = releaseRange{version: t.Name, date: t.Date, to: t.Name}, t.Commit
+	}
+	current.from = from
+	return append(ranges, current), nil
+}
+
+// release reads the commits of r and groups them
+func (r releaseRange) release(ctx context.Context) (changelog.Release, error) {
+	rev := r.to
+	if r.from != "" {
+		rev = r.from + ".." + r.to
+	}
+	commits, err := git.Log(ctx, "--no-merges", rev)
+	if err != nil {
+		return changelog.Release{}, err
+	}
+	list := make([]changelog.Commit, len(commits))
+	for i, c := range commits {
+		list[i] = changelog.Commit{Hash: c.Hash, Message: c.Message}
+	}
+	return changelog.NewRelease(r.version, r.date, list), nil
+}
+
+// changelogRange resolves the argument of changelog into from and to. Without
+// one the changes since the latest tag are listed, or since the tag before
+// when HEAD is tagged, or all of them with all.
+func changelogRange(ctx context.Context, arg string, all bool) (string, string, error) {
+	if arg == "" {
+		if all {
+			return "", "HEAD", nil
+		}
+		from, err := git.LatestTag(ctx, "HEAD")
+		if err != nil || from == "" {
+			return from, "HEAD", err
+		}
+		// Right after tagging, list the release just tagged
+		head, _ := git.RevParse(ctx, "HEAD")
+		if tagged, _ := git.RevParse(ctx, from); tagged == head {
+			if _, err := git.RevParse(ctx, "HEAD^"); err != nil {
+				return "", "HEAD", nil
+			}
+			from, err = git.LatestTag(ctx, "HEAD^")
+		}
+		return from, "HEAD", err
+	}
+	from, to, ok := strings.Cut(arg, "..")
+	if !ok {
+		from, to = arg, "HEAD"
+	}
+	if strings.HasPrefix(to, ".") {
+		return "", "", fmt.Errorf("changelog takes a <from>..<to> range, not %q", arg)
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	return from, to, nil
+}
+
+// summarize asks the provider for a paragraph introducing each release
+func summarize(cmd *cobra.Command, cfg *config.Config, releases []changelog.Release, userPrompt string) error {
+	provider, err := llm.GetProvider(cfg.DefaultProvider)
+	if err != nil {
+		return err
+	}
+	var b prompt.Builder
+	if cfg.Language != "" {
+		b.Add("Language", fmt.Sprintf("Write the summary in %s.", cfg.Language))
+	}
+	b.Add("", userPrompt)
+
+	// Commit subjects can quote secrets too; redacted before the spinner starts
+	// since redaction may ask whether to go on
+	sections := make([]string, len(releases))
+	for i := range releases {
+		if _, sections[i], err = redactRequest(cmd, cfg, provider, cfg.DefaultProvider, "", changelog.Markdown(releases[i:i+1]), changelogLabel); err != nil {
+			return err
+		}
+	}
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithColor("cyan"), spinner.WithWriterFile(os.Stderr))
+	s.Suffix = " " + changelogLabel.progress + "..."
+	s.Start()
+	defer s.Stop()
+
+	ctx := llm.WithSystemPrompt(cmd.Context(), summaryPrompt)
+	for i := range releases {
+		req := &messageRequest{
+			provider:     provider,
+			providerName: cfg.DefaultProvider,
+			model:        cfg.Model,
+			diff:         sections[i],
+			prompt:       b.String(),
+		}
+		summary, _, err := req.run(ctx)
+		if err != nil {
+			s.Stop()
+			return providerError(cmd, cfg.DefaultProvider, err)
+		}
+		releases[i].Summary = strings.TrimSpace(summary)
+	}
+	return nil
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog [<from>..<to>]",
+	Short: "Write a Keep a Changelog section from Conventional Commits",
+	Long: `List the Conventional Commits between two revisions as Keep a Changelog Markdown, one release per tag in the range, grouped into Added, Changed, Deprecated, Removed, Fixed and Security and sorted by scope. Breaking changes are listed first in every release; commits that are not Conventional or do not change behaviour, such as docs and chore, are left out.
+
+Without a range the changes since the latest tag are listed as Unreleased; --all lists the whole history.
+
+  vibecheck changelog v1.2.0..HEAD
+  vibecheck changelog --all --prepend
+  vibecheck changelog --summary --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		all, err := cmd.Flags().GetBool(allFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool all flag: %w", err)
+		}
+		summary, err := cmd.Flags().GetBool(summaryFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool summary flag: %w", err)
+		}
+		format, err := cmd.Flags().GetString(outputFlagName)
+		if err != nil {
+			return fmt.Errorf("get string output flag: %w", err)
+		}
+		if format != outputMarkdown && format != outputJSON {
+			return fmt.Errorf("unknown --%s %q: use %s or %s", outputFlagName, format, outputMarkdown, outputJSON)
+		}
+		prepend, err := cmd.Flags().GetString(prependFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prepend flag: %w", err)
+		}
+		if prepend != "" && format == outputJSON {
+			return fmt.Errorf("--%s writes Markdown and cannot be used with --%s %s", prependFlagName, outputFlagName, outputJSON)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+
+		arg := ""
+		if len(args) == 1 {
+			arg = args[0]
+		}
+		if arg != "" && all {
+			return fmt.Errorf("--%s and a range cannot be used together", allFlagName)
+		}
+		from, to, err := changelogRange(ctx, arg, all)
+		if err != nil {
+			return err
+		}
+		ranges, err := planChangelog(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		var releases []changelog.Release
+		for _, r := range ranges {
+			release, err := r.release(ctx)
+			if err != nil {
+				return err
+			}
+			if !release.Empty() {
+				releases = append(releases, release)
+			}
+		}
+		if len(releases) == 0 {
+			return errors.New("no Conventional Commits to list")
+		}
+
+		if summary {
+			if err := summarize(cmd, cfg.Config, releases, additionalPrompt); err != nil {
+				return err
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if format == outputJSON {
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(releases)
+		}
+		markdown := changelog.Markdown(releases)
+		if prepend == "" {
+			fmt.Fprint(out, markdown)
+			return nil
+		}
+		existing, err := os.ReadFile(prepend)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.WriteFile(prepend, []byte(changelog.Prepend(string(existing), markdown)), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Updated %s\n", prepend)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+	changelogCmd.Flags().String(promptFlagName, "", "used to provide additional context for the release summaries")
+	changelogCmd.Flags().String(providerFlagName, "", "used to select the ai-provider that writes the release summaries")
+	changelogCmd.Flags().String(modelFlagName, "", "used to override the model of the selected provider")
+	changelogCmd.Flags().String(languageFlagName, "", "used to select the language the release summaries are written in")
+	changelogCmd.Flags().Bool(allFlagName, false, "used to list the whole history instead of the changes since the latest tag")
+	changelogCmd.Flags().Bool(summaryFlagName, false, "used to ask the provider for a paragraph introducing each release")
+	changelogCmd.Flags().String(outputFlagName, outputMarkdown, "used to select the output format: markdown or json")
+	changelogCmd.Flags().String(prependFlagName, "", "used to add the releases to the top of a changelog file, replacing its Unreleased section (default file "+defaultChangelog+")")
+	changelogCmd.Flags().Lookup(prependFlagName).NoOptDefVal = defaultChangelog
+}
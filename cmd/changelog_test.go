@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/changelog"
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestPlanChangelog(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	run("commit", "-q", "--allow-empty", "-m", "feat: one")
+	run("tag", "v1.0.0")
+	run("commit", "-q", "--allow-empty", "-m", "fix: two")
+	run("tag", "v1.0.1")
+	run("tag", "latest")
+	run("commit", "-q", "--allow-empty", "-m", "docs: three")
+	run("commit", "-q", "--allow-empty", "-m", "feat: four")
+
+	ctx := context.Background()
+	from, to, err := changelogRange(ctx, "", false)
+	if err != nil || to != "HEAD" || (from != "v1.0.1" && from != "latest") {
+		t.Errorf("changelogRange() = %q, %q, %v", from, to, err)
+	}
+
+	ranges, err := planChangelog(ctx, "", "HEAD")
+	if err != nil {
+		t.Fatalf("planChangelog() error = %v", err)
+	}
+	var versions []string
+	for _, r := range ranges {
+		versions = append(versions, r.version)
+	}
+	if len(versions) != 3 || versions[0] != changelog.Unreleased || versions[2] != "v1.0.0" {
+		t.Errorf("planChangelog() versions = %v", versions)
+	}
+
+	var releases []changelog.Release
+	for _, r := range ranges {
+		release, err := r.release(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases = append(releases, release)
+	}
+	if got := releases[0].Sections; len(got) != 1 || len(got[0].Entries) != 1 || got[0].Entries[0].Description != "four" {
+		t.Errorf("Unreleased sections = %+v", got)
+	}
+	if got := releases[2].Sections; len(got) != 1 || got[0].Name != changelog.Added {
+		t.Errorf("v1.0.0 sections = %+v", got)
+	}
+
+	// A range ending at a tag names the first release after it
+	ranges, err = planChangelog(ctx, "v1.0.0", "v1.0.1")
+	if err != nil {
+		t.Fatalf("planChangelog(range) error = %v", err)
+	}
+	if len(ranges) != 1 || ranges[0].from != "v1.0.0" || (ranges[0].version != "v1.0.1" && ranges[0].version != "latest") {
+		t.Errorf("planChangelog(range) = %+v", ranges)
+	}
+
+	// Right after tagging the release just tagged is listed
+	run("tag", "v1.1.0")
+	if from, _, err := changelogRange(ctx, "", false); err != nil || (from != "v1.0.1" && from != "latest") {
+		t.Errorf("changelogRange(tagged HEAD) = %q, %v", from, err)
+	}
+
+	for arg, want := range map[string][2]string{"v1.0.0..": {"v1.0.0", "HEAD"}, "v1.0.0": {"v1.0.0", "HEAD"}, "v1.0.0..v1.0.1": {"v1.0.0", "v1.0.1"}} {
+		from, to, err := changelogRange(ctx, arg, false)
+		if err != nil || !reflect.DeepEqual([2]string{from, to}, want) {
+			t.Errorf("changelogRange(%q) = %q, %q, %v", arg, from, to, err)
+		}
+	}
+	if _, _, err := changelogRange(ctx, "v1.0.0...HEAD", false); err == nil {
+		t.Error("changelogRange(symmetric) error = nil")
+	}
+}
+
+func TestSummarizeRedacts(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.SetErr(&stderr)
+	releases := []changelog.Release{{
+		Version:  "1.0.0",
+		Sections: []changelog.Section{{Name: "Fixed", Entries: []changelog.Entry{{Type: "fix", Description: "rotate sk-proj-abcdefghijklmnopqrstuvwx", Hash: "abc1234"}}}},
+	}}
+
+	// The provider is never called once redaction blocks the request
+	cfg := &config.Config{DefaultProvider: "openai", Redact: config.RedactConfig{Block: true}}
+	if err := summarize(cmd, cfg, releases, ""); !errors.Is(err, errSecretsFound) {
+		t.Errorf("summarize() error = %v, want errSecretsFound", err)
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("changelog: openai-key (secret)")) {
+		t.Errorf("report = %q", stderr.String())
+	}
+}
@@ -6,7 +6,9 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -24,6 +26,8 @@ import (
 	_ "github.com/rshdhere/vibecheck/internal/llm/openai"
 	_ "github.com/rshdhere/vibecheck/internal/llm/perplexity"
 	_ "github.com/rshdhere/vibecheck/internal/llm/qwen"
+	"github.com/rshdhere/vibecheck/internal/prompt"
+	"github.com/rshdhere/vibecheck/internal/provenance"
 	"github.com/rshdhere/vibecheck/internal/stats"
 	"github.com/rshdhere/vibecheck/internal/ui/notify"
 	"github.com/spf13/cobra"
@@ -32,69 +36,222 @@ import (
 const (
 	promptFlagName   = "prompt"
 	providerFlagName = "provider"
+	modelFlagName    = "model"
+	styleFlagName    = "style"
+	languageFlagName = "language"
+	issueFlagName    = "issue"
+	forceLLMFlagName = "force-llm"
+	allFlagName      = "all"
+
+	amendFlagName      = "amend"
+	noVerifyFlagName   = "no-verify"
+	gpgSignFlagName    = "gpg-sign"
+	authorFlagName     = "author"
+	dateFlagName       = "date"
+	allowEmptyFlagName = "allow-empty"
+	fixupFlagName      = "fixup"
+	noEditFlagName     = "no-edit"
 )
 
 type ProviderFunc func(context.Context, string, string) (string, error)
 
 var commitCmd = &cobra.Command{
-	Use:     "commit",
+	Use:     "commit [--all] [--amend] [-- <pathspec>...]",
 	Short:   "A command-line tool for easing git commit messages for me(or may be you guys too lol), adding multiple models to it sounds cool right?!",
 	Long:    `A complete solution for vibecoders to vibecheck their code and save it locally even before it messess-up your production, vibecheck is a check point were they can automate their commit message to models like gpt-oss:20b, GPT4o-mini, Gemini-2.5-Flash, Claude-3.5-Haiku, Llama-3.3-70b (via Groq), Grok-beta, Kimi K2, Qwen-Turbo, DeepSeek-Chat, and Perplexity Sonar`,
 	Version: version,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		diff, err := git.StagedDiff(cmd.Context())
+		cfg, err := resolveConfig(cmd)
 		if err != nil {
-			return fmt.Errorf("staged changes: %w", err)
+			return fmt.Errorf("resolve config: %w", err)
 		}
-		if strings.TrimSpace(diff) == "" {
-			notify.ShowStageReminder()
+
+		copts, err := commitOptions(cmd, args)
+		if err != nil {
+			return err
+		}
+		dry, err := dryRun(cmd)
+		if err != nil {
+			return err
+		}
+		format, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		// Notifications must not wait for a key or draw over piped output
+		if !interactive(cmd) || format == outputJSON {
+			notify.SetPlain(true)
+		}
+		// git's commit summary would break the JSON on stdout
+		copts.Quiet = format == outputJSON
+
+		all, err := cmd.Flags().GetBool(allFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool all flag: %w", err)
+		}
+		// As with git commit, -a stages every tracked change and pathspecs
+		// commit the current content of the matching tracked files
+		pathspecs := copts.Pathspecs
+		if all && len(pathspecs) > 0 {
+			return fmt.Errorf("--%s with paths does not make sense", allFlagName)
+		}
+		if dry && (all || len(pathspecs) > 0 || copts.Fixup != "") {
+			return fmt.Errorf("--%s leaves the index alone; stage the changes instead of using --%s, --%s or paths", dryRunFlagName, allFlagName, fixupFlagName)
+		}
+		if all || len(pathspecs) > 0 {
+			if err := git.StageTracked(cmd.Context(), pathspecs...); err != nil {
+				return err
+			}
+		}
+
+		// git writes "fixup!" messages itself
+		if copts.Fixup != "" {
+			if err := git.CommitWMessage(cmd.Context(), "", copts); err != nil {
+				return fmt.Errorf("commit fixup: %w", err)
+			}
 			return nil
 		}
 
+		// An amended commit is described by everything it will contain
+		base := ""
+		if copts.Amend {
+			if base, err = git.Parent(cmd.Context(), "HEAD"); err != nil {
+				return fmt.Errorf("amend: %w", err)
+			}
+		}
+
+		opts, err := diffOptions(cfg.Diff)
+		if err != nil {
+			return err
+		}
+		// Local analysis always reads the patch; stat-only only limits what the provider sees
+		patchOpts := opts
+		patchOpts.StatOnly = false
+		diff, err := stagedDiff(cmd.Context(), base, patchOpts, pathspecs...)
+		if err != nil {
+			return fmt.Errorf("staged changes: %w", err)
+		}
+
 		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
 		if err != nil {
 			return fmt.Errorf("get string prompt flag: %w", err)
 		}
+		if strings.TrimSpace(diff) == "" {
+			if !copts.AllowEmpty {
+				notify.ShowStageReminder()
+				return reportedError(cmd, exitNothingStaged, errors.New("nothing is staged"))
+			}
+			if additionalPrompt == "" {
+				return fmt.Errorf("nothing is staged; describe the empty commit with --%s", promptFlagName)
+			}
+		}
 
-		providerName, err := cmd.Flags().GetString(providerFlagName)
+		issueKey, err := cmd.Flags().GetString(issueFlagName)
 		if err != nil {
-			return fmt.Errorf("get string provider flag: %w", err)
+			return fmt.Errorf("get string issue flag: %w", err)
 		}
 
-		provider, err := llm.GetProvider(providerName)
+		a, err := analyzeDiff(cmd.Context(), cfg, diff, stagedSource(base, pathspecs), issueKey)
 		if err != nil {
 			return err
 		}
+		if err := a.requireIssue(cfg); err != nil {
+			return err
+		}
+		if opts.StatOnly {
+			a.statOnly = true
+			if a.diff, err = stagedDiff(cmd.Context(), base, opts, pathspecs...); err != nil {
+				return fmt.Errorf("staged diffstat: %w", err)
+			}
+		}
 
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithColor("cyan"))
+		var topts trailerOptions
+		if topts.signoff, err = cmd.Flags().GetBool(signoffFlagName); err != nil {
+			return fmt.Errorf("get bool signoff flag: %w", err)
+		}
+		if topts.coauthors, err = cmd.Flags().GetStringSlice(coauthorFlagName); err != nil {
+			return fmt.Errorf("get string slice co-author flag: %w", err)
+		}
 
-		s.Suffix = " Generating commit message..."
-		s.Start()
-		defer s.Stop()
+		// Changes vibecheck can describe exactly skip the provider entirely
+		generated := a.localMessage == ""
+		message, latency := a.localMessage, 0.0
+		var usage llm.Usage
+		if generated {
+			cmd.SetContext(llm.WithUsage(cmd.Context(), &usage))
+			message, latency, err = generateMessage(cmd, cfg.Config, a, additionalPrompt)
+			if err != nil {
+				return err
+			}
+		}
 
-		ctx, cancel := context.WithTimeout(cmd.Context(), time.Second*60)
-		defer cancel()
+		message, err = a.finalize(message)
+		if err != nil {
+			return err
+		}
+
+		isBreaking, err := confirmBreaking(cmd, a.breaking)
+		if err != nil {
+			return fmt.Errorf("confirm breaking change: %w", err)
+		}
+		if isBreaking {
+			message = markBreaking(message, a.breaking)
+		}
 
-		// Track latency
-		startTime := time.Now()
-		message, err := provider.GenerateCommitMessage(ctx, diff, additionalPrompt)
-		latency := time.Since(startTime).Seconds()
+		record := provenance.Record{
+			Tool:          provenance.Tool,
+			Version:       version,
+			Provider:      cfg.DefaultProvider,
+			Model:         providerModel(cfg.DefaultProvider, cfg.Model),
+			Latency:       latency,
+			PromptVersion: llm.PromptVersion,
+			DiffHash:      provenance.HashDiff(a.sent),
+		}
+		if generated && cfg.Provenance.Trailer {
+			topts.generatedBy = record.TrailerValue()
+		}
+
+		trailers, err := collectTrailers(cmd.Context(), cfg.Config, a, topts)
 		if err != nil {
-			s.Stop()
-			if envVar := detectMissingEnvVar(err); envVar != "" {
-				notify.ShowMissingAPIKey(providerName, envVar)
-				return nil
+			return err
+		}
+		message, err = git.InterpretTrailers(cmd.Context(), message, trailers)
+		if err != nil {
+			return err
+		}
+
+		res := result{Message: message, Local: !generated}
+		if generated {
+			res.Provider, res.Model = record.Provider, record.Model
+			res.Latency, res.Usage = latency, usage
+		}
+		if dry {
+			return writeResult(cmd.OutOrStdout(), format, res)
+		}
+
+		if err := git.CommitWMessage(cmd.Context(), message, copts); err != nil {
+			return fmt.Errorf("commit with message: %w", err)
+		}
+		if format == outputJSON {
+			// Report the commit as made, after the editor and commit-msg hooks
+			res.Committed = true
+			if head, err := git.Log(cmd.Context(), "-1", "HEAD"); err == nil && len(head) == 1 {
+				res.Commit, res.Message = head[0].Hash, head[0].Message
 			}
-			if model := detectMissingModel(err); model != "" {
-				notify.ShowMissingModel(providerName, model)
-				return nil
+			if err := writeResult(cmd.OutOrStdout(), format, res); err != nil {
+				return err
 			}
-			return fmt.Errorf("generated commit message: %w", err)
 		}
-		s.Stop()
 
-		if err := git.CommitWMessage(cmd.Context(), message); err != nil {
-			return fmt.Errorf("commit with message: %w", err)
+		if !generated {
+			return nil
+		}
+
+		if cfg.Provenance.Notes {
+			// The commit already exists, so a failed note is only worth a warning
+			if err := addProvenanceNote(cmd.Context(), "HEAD", record); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: provenance note: %v\n", err)
+			}
 		}
 
 		// Record stats after successful commit
@@ -103,7 +260,7 @@ var commitCmd = &cobra.Command{
 		if idx := strings.Index(message, "\n"); idx > 0 {
 			commitMsg = message[:idx]
 		}
-		if err := stats.RecordCommit(providerName, latency, commitMsg); err != nil {
+		if err := stats.RecordCommit(cfg.DefaultProvider, latency, commitMsg); err != nil {
 			// Don't fail the commit if stats recording fails
 			// Just log it silently
 			_ = err
@@ -125,8 +282,199 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// commitCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	commitCmd.Flags().String(promptFlagName, "", "used to provide additional context to llm")
-	commitCmd.Flags().String(providerFlagName, config.GetDefaultProvider(), fmt.Sprintf("used to select a particular ai-provider: %v (use 'vibecheck models' to change default)", strings.Join(llm.GetRegisteredNames(), ",")))
+	addMessageFlags(commitCmd)
+	commitCmd.Flags().BoolP(allFlagName, "a", false, "used to stage every modified and deleted tracked file first, like git commit -a")
+	commitCmd.Flags().BoolP(signoffFlagName, "s", false, "used to add a Signed-off-by trailer for the committer (DCO)")
+	commitCmd.Flags().StringSlice(coauthorFlagName, nil, "used to add Co-authored-by trailers, by configured alias or \"Name <email>\"")
+	addCommitFlags(commitCmd)
+	addScriptFlags(commitCmd)
+	addDiffFlags(commitCmd)
+}
+
+// addCommitFlags registers the git commit flags that are passed through on cmd
+func addCommitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(amendFlagName, false, "used to replace the last commit, describing everything it will contain")
+	cmd.Flags().BoolP(noVerifyFlagName, "n", false, "used to skip the pre-commit and commit-msg hooks")
+	cmd.Flags().StringP(gpgSignFlagName, "S", "", "used to GPG or SSH sign the commit, optionally with a key id (--gpg-sign=<keyid>)")
+	cmd.Flags().Lookup(gpgSignFlagName).NoOptDefVal = defaultSigningKey
+	cmd.Flags().String(authorFlagName, "", "used to override the commit author, \"Name <email>\"")
+	cmd.Flags().String(dateFlagName, "", "used to override the author date")
+	cmd.Flags().Bool(allowEmptyFlagName, false, "used to record a commit without changes, described by --prompt")
+	cmd.Flags().String(fixupFlagName, "", "used to create a fixup! commit for the given commit, without generating a message")
+	cmd.Flags().Bool(noEditFlagName, false, "used to commit the generated message without opening the editor")
+	cmd.Flags().BoolP(yesFlagName, "y", false, "used to run without any questions or editor, for scripts and CI (implies --no-edit)")
+}
+
+// defaultSigningKey is the value of a bare -S, which signs with git's configured key
+const defaultSigningKey = "default"
+
+// commitOptions reads the git commit flags passed through by vibecheck commit
+func commitOptions(cmd *cobra.Command, pathspecs []string) (git.CommitOptions, error) {
+	opts := git.CommitOptions{Pathspecs: pathspecs}
+	flags := cmd.Flags()
+	var err error
+	if opts.Amend, err = flags.GetBool(amendFlagName); err != nil {
+		return opts, fmt.Errorf("get bool amend flag: %w", err)
+	}
+	if opts.NoVerify, err = flags.GetBool(noVerifyFlagName); err != nil {
+		return opts, fmt.Errorf("get bool no-verify flag: %w", err)
+	}
+	if opts.Sign = flags.Changed(gpgSignFlagName); opts.Sign {
+		if opts.SignKey, err = flags.GetString(gpgSignFlagName); err != nil {
+			return opts, fmt.Errorf("get string gpg-sign flag: %w", err)
+		}
+		if opts.SignKey == defaultSigningKey {
+			opts.SignKey = ""
+		}
+	}
+	if opts.Author, err = flags.GetString(authorFlagName); err != nil {
+		return opts, fmt.Errorf("get string author flag: %w", err)
+	}
+	if opts.Date, err = flags.GetString(dateFlagName); err != nil {
+		return opts, fmt.Errorf("get string date flag: %w", err)
+	}
+	if opts.AllowEmpty, err = flags.GetBool(allowEmptyFlagName); err != nil {
+		return opts, fmt.Errorf("get bool allow-empty flag: %w", err)
+	}
+	if opts.Fixup, err = flags.GetString(fixupFlagName); err != nil {
+		return opts, fmt.Errorf("get string fixup flag: %w", err)
+	}
+	if opts.NoEdit, err = flags.GetBool(noEditFlagName); err != nil {
+		return opts, fmt.Errorf("get bool no-edit flag: %w", err)
+	}
+	yes, err := flags.GetBool(yesFlagName)
+	if err != nil {
+		return opts, fmt.Errorf("get bool yes flag: %w", err)
+	}
+	opts.NoEdit = opts.NoEdit || yes
+	if opts.Fixup != "" && opts.Amend {
+		return opts, fmt.Errorf("--%s and --%s cannot be used together", fixupFlagName, amendFlagName)
+	}
+	return opts, nil
+}
+
+// addMessageFlags registers the flags that shape a generated message on cmd
+func addMessageFlags(cmd *cobra.Command) {
+	cmd.Flags().String(promptFlagName, "", "used to provide additional context to llm")
+	cmd.Flags().String(providerFlagName, "", fmt.Sprintf("used to select a particular ai-provider: %v (default %q, use 'vibecheck models' to change it)", strings.Join(llm.GetRegisteredNames(), ","), config.GetDefaultProvider()))
+	cmd.Flags().String(modelFlagName, "", "used to override the model of the selected provider")
+	cmd.Flags().String(styleFlagName, "", "used to describe the commit message style, e.g. \"conventional, no bullets\"")
+	cmd.Flags().String(languageFlagName, "", "used to select the language the commit message is written in")
+	cmd.Flags().String(issueFlagName, "", "used to reference an issue key instead of the one found in the branch name")
+	cmd.Flags().Bool(forceLLMFlagName, false, "used to ask the provider even for changes vibecheck describes locally (dependency bumps, renames, formatting)")
+	cmd.Flags().Bool(breakingFlagName, false, "used to mark the commit as a breaking change without asking (--breaking=false never marks it)")
+}
+
+// generateMessage asks the configured provider for a commit message. A missing
+// API key or model is shown to the user and returned as an already reported
+// error. Token usage is reported to an llm.Usage in the command context.
+func generateMessage(cmd *cobra.Command, cfg *config.Config, a *analysis, additionalPrompt string) (string, float64, error) {
+	req, err := newMessageRequest(cmd, cfg, a, additionalPrompt)
+	if err != nil {
+		return "", 0, err
+	}
+	return runRequest(cmd, req)
+}
+
+// runRequest calls the provider for req behind a spinner, like generateMessage
+func runRequest(cmd *cobra.Command, req *messageRequest) (string, float64, error) {
+	// The spinner stays on stderr so the message can be piped from stdout
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithColor("cyan"), spinner.WithWriterFile(os.Stderr))
+
+	s.Suffix = " " + req.label.progress + "..."
+	s.Start()
+	defer s.Stop()
+
+	message, latency, err := req.run(cmd.Context())
+	s.Stop()
+	if err == nil && strings.TrimSpace(message) == "" {
+		err = errEmptyMessage
+	}
+	if err != nil {
+		return "", 0, providerError(cmd, req.providerName, err)
+	}
+	return message, latency, nil
+}
+
+// errEmptyMessage is returned when the provider answers without a message
+var errEmptyMessage = errors.New("the provider returned an empty message")
+
+// messageRequest is a prepared provider call for one commit message
+type messageRequest struct {
+	provider     llm.Provider
+	providerName string
+	model        string
+	diff         string
+	prompt       string
+	label        requestLabel
+}
+
+// newMessageRequest prepares the provider call for a, redacting the diff and
+// the prompt first
+func newMessageRequest(cmd *cobra.Command, cfg *config.Config, a *analysis, additionalPrompt string) (*messageRequest, error) {
+	return newRequest(cmd, cfg, a, buildPromptContext(cfg, a, additionalPrompt), commitLabel)
+}
+
+// newRequest is newMessageRequest for a prompt built by the caller, for
+// requests other than commit messages
+func newRequest(cmd *cobra.Command, cfg *config.Config, a *analysis, prompt string, label requestLabel) (*messageRequest, error) {
+	provider, err := llm.GetProvider(cfg.DefaultProvider)
+	if err != nil {
+		return nil, err
+	}
+	// The prompt can quote commit messages, as squash and pr do
+	providerDiff, providerPrompt, err := redactRequest(cmd, cfg, provider, cfg.DefaultProvider, a.diff, prompt, label)
+	if err != nil {
+		return nil, err
+	}
+	a.sent = providerDiff
+	return &messageRequest{
+		provider:     provider,
+		providerName: cfg.DefaultProvider,
+		model:        cfg.Model,
+		diff:         providerDiff,
+		prompt:       providerPrompt,
+		label:        label,
+	}, nil
+}
+
+// run calls the provider and returns the message and the latency in seconds
+func (r *messageRequest) run(ctx context.Context) (string, float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*60)
+	defer cancel()
+	ctx = llm.WithModel(ctx, r.model)
+
+	startTime := time.Now()
+	message, err := r.provider.GenerateCommitMessage(ctx, r.diff, r.prompt)
+	return message, time.Since(startTime).Seconds(), err
+}
+
+// providerError tells the user about a missing API key or model, which is then
+// returned as reported, and wraps any other provider error
+func providerError(cmd *cobra.Command, providerName string, err error) error {
+	if envVar := detectMissingEnvVar(err); envVar != "" {
+		notify.ShowMissingAPIKey(providerName, envVar)
+		return reportedError(cmd, exitMissingSetup, fmt.Errorf("%s is not set", envVar))
+	}
+	if model := detectMissingModel(err); model != "" {
+		notify.ShowMissingModel(providerName, model)
+		return reportedError(cmd, exitMissingSetup, fmt.Errorf("model %q not found", model))
+	}
+	return fmt.Errorf("generated commit message: %w", err)
+}
+
+// buildPromptContext merges the configured style, language and instructions
+// and the local analysis with the user supplied prompt
+func buildPromptContext(cfg *config.Config, a *analysis, userPrompt string) string {
+	var b prompt.Builder
+	b.Add("Commit message style", cfg.Style)
+	if cfg.Language != "" {
+		b.Add("Language", fmt.Sprintf("Write the commit message in %s.", cfg.Language))
+	}
+	a.addPromptSections(&b)
+	b.Add("Repository instructions", cfg.Instructions)
+	b.Add("", userPrompt)
+	return b.String()
 }
 
 func detectMissingEnvVar(err error) string {
@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/spf13/cobra"
 )
 
 func TestDetectMissingEnvVar(t *testing.T) {
@@ -105,3 +111,60 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestBuildPromptContext(t *testing.T) {
+	cfg := &config.Config{
+		Style:        "terse",
+		Language:     "German",
+		Instructions: "mention ticket numbers",
+	}
+	a := &analysis{allowedScopes: []string{"cli", "llm"}, dominantScopes: []string{"llm"}}
+
+	got := buildPromptContext(cfg, a, "fixed bug in parser")
+	for _, want := range []string{"terse", "German", "cli, llm", "should be: llm", "mention ticket numbers", "fixed bug in parser"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildPromptContext() = %q, missing %q", got, want)
+		}
+	}
+
+	if got := buildPromptContext(&config.Config{}, &analysis{}, ""); got != "" {
+		t.Errorf("buildPromptContext() with empty config = %q, want empty", got)
+	}
+}
+
+func TestCommitOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    git.CommitOptions
+		wantErr bool
+	}{
+		{name: "defaults", want: git.CommitOptions{}},
+		{
+			name: "passthrough",
+			args: []string{"--amend", "-n", "--author", "Sam Roe <sam@example.com>", "--date", "yesterday", "--allow-empty", "--no-edit"},
+			want: git.CommitOptions{Amend: true, NoVerify: true, Author: "Sam Roe <sam@example.com>", Date: "yesterday", AllowEmpty: true, NoEdit: true},
+		},
+		{name: "yes", args: []string{"-y"}, want: git.CommitOptions{NoEdit: true}},
+		{name: "sign with default key", args: []string{"-S"}, want: git.CommitOptions{Sign: true}},
+		{name: "sign with key", args: []string{"--gpg-sign=ABC123"}, want: git.CommitOptions{Sign: true, SignKey: "ABC123"}},
+		{name: "fixup", args: []string{"--fixup", "HEAD~2"}, want: git.CommitOptions{Fixup: "HEAD~2"}},
+		{name: "fixup and amend", args: []string{"--fixup", "HEAD~2", "--amend"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addCommitFlags(cmd)
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := commitOptions(cmd, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("commitOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commitOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
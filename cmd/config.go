@@ -0,0 +1,325 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/keys"
+	"github.com/spf13/cobra"
+)
+
+const (
+	originFlagName  = "origin"
+	jsonFlagName    = "json"
+	repoFlagName    = "repo"
+	globalFlagName  = "global"
+	profileFlagName = "profile"
+)
+
+// flagKeys maps command-line flags to the config keys they override
+var flagKeys = map[string]string{
+	providerFlagName: "default_provider",
+	modelFlagName:    "model",
+	styleFlagName:    "style",
+	languageFlagName: "language",
+	profileFlagName:  "profile",
+	forceLLMFlagName: "force_llm",
+
+	diffContextFlagName:     "diff.context",
+	functionContextFlagName: "diff.function_context",
+	whitespaceFlagName:      "diff.whitespace",
+	renamesFlagName:         "diff.renames",
+	copiesFlagName:          "diff.copies",
+	diffAlgorithmFlagName:   "diff.algorithm",
+	submoduleFlagName:       "diff.submodule",
+	statOnlyFlagName:        "diff.stat_only",
+}
+
+// resolveConfig layers the global, repository, environment and flag settings
+// for the repository the command runs in
+func resolveConfig(cmd *cobra.Command) (*config.Resolved, error) {
+	root, err := git.TopLevel(cmd.Context())
+	if err != nil {
+		root = ""
+	}
+	var remotes []string
+	if root != "" {
+		remotes, _ = git.RemoteURLs(cmd.Context())
+	}
+
+	var overrides []config.Override
+	for flag, key := range flagKeys {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil || !f.Changed {
+			continue
+		}
+		overrides = append(overrides, config.Override{Key: key, Flag: flag, Value: f.Value.String()})
+	}
+
+	cfg, err := config.Resolve(config.ResolveOptions{RepoRoot: root, Remotes: remotes, Flags: overrides})
+	if err != nil {
+		return nil, err
+	}
+	if err := keys.UseKeySet(cfg.Keys); err != nil {
+		return nil, fmt.Errorf("keys, set in %s: %w", cfg.Origin("keys"), err)
+	}
+	return cfg, nil
+}
+
+// configTargetPath returns the file a config subcommand reads from or writes to
+func configTargetPath(cmd *cobra.Command) (string, error) {
+	useRepo, _ := cmd.Flags().GetBool(repoFlagName)
+	if !useRepo {
+		return config.GlobalPath()
+	}
+
+	root, err := git.TopLevel(cmd.Context())
+	if err != nil {
+		return "", fmt.Errorf("--repo requires a git repository: %w", err)
+	}
+	path, _ := config.RepoConfigPath(root)
+	return path, nil
+}
+
+// loadConfigView returns the configuration a read-only subcommand should show:
+// a single file with --repo or --global, otherwise the effective layered config
+func loadConfigView(cmd *cobra.Command) (*config.Resolved, error) {
+	useRepo, _ := cmd.Flags().GetBool(repoFlagName)
+	useGlobal, _ := cmd.Flags().GetBool(globalFlagName)
+	if !useRepo && !useGlobal {
+		return resolveConfig(cmd)
+	}
+
+	path, err := configTargetPath(cmd)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &config.Resolved{Config: cfg, Origins: map[string]config.Origin{}}, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change vibecheck configuration without the TUI",
+	Long: `Inspect and change vibecheck configuration using dotted keys. Settings are layered as global (~/.vibecheck.json) < repository (.vibecheck.yaml at the repo root) < VIBECHECK_* environment variables < command-line flags.
+
+Writes go to the global file unless --repo is given, and are merged into the existing file.`,
+}
+
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"show"},
+	Short:   "List the effective configuration for the current repository",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		showOrigin, err := cmd.Flags().GetBool(originFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool origin flag: %w", err)
+		}
+		asJSON, err := cmd.Flags().GetBool(jsonFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool json flag: %w", err)
+		}
+
+		cfg, err := loadConfigView(cmd)
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		if asJSON {
+			out := map[string]any{}
+			for _, f := range config.Fields(cfg.Config) {
+				value, err := cfg.Get(f.Key)
+				if err != nil {
+					return err
+				}
+				if showOrigin {
+					origin := cfg.Origin(f.Key)
+					out[f.Key] = map[string]any{"value": value, "source": origin.Source, "detail": origin.Detail}
+					continue
+				}
+				out[f.Key] = value
+			}
+			return writeJSON(cmd.OutOrStdout(), out)
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for _, f := range config.Fields(cfg.Config) {
+			value, err := cfg.Get(f.Key)
+			if err != nil {
+				return err
+			}
+			if showOrigin {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", f.Key, config.FormatValue(value), cfg.Origin(f.Key))
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\n", f.Key, config.FormatValue(value))
+		}
+		return w.Flush()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a single configuration key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, err := cmd.Flags().GetBool(jsonFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool json flag: %w", err)
+		}
+
+		cfg, err := loadConfigView(cmd)
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			return writeJSON(cmd.OutOrStdout(), value)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), config.FormatValue(value))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration key, validating the value against its type",
+	Long:  `Set a configuration key. Lists are written comma separated (a,b,c) and maps as comma separated key=value pairs.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configTargetPath(cmd)
+		if err != nil {
+			return err
+		}
+		if useRepo, _ := cmd.Flags().GetBool(repoFlagName); useRepo && config.UserOnly(args[0]) {
+			return fmt.Errorf("%s cannot be set in a repository config; set it without --%s", args[0], repoFlagName)
+		}
+		if args[0] == "keys" || strings.HasPrefix(args[0], "profiles.") && strings.HasSuffix(args[0], ".keys") {
+			if err := keys.CheckKeySet(args[1]); err != nil {
+				return fmt.Errorf("set %s: %w", args[0], err)
+			}
+		}
+		if err := config.SetValue(path, args[0], args[1]); err != nil {
+			return fmt.Errorf("set %s: %w", args[0], err)
+		}
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration key so lower layers apply again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configTargetPath(cmd)
+		if err != nil {
+			return err
+		}
+		if err := config.UnsetValue(path, args[0]); err != nil {
+			return fmt.Errorf("unset %s: %w", args[0], err)
+		}
+		return nil
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of the configuration file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configTargetPath(cmd)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in $EDITOR",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configTargetPath(cmd)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			initial := "{}\n"
+			if !strings.HasSuffix(path, ".json") {
+				initial = ""
+			}
+			if err := os.WriteFile(path, []byte(initial), 0644); err != nil {
+				return fmt.Errorf("create %s: %w", path, err)
+			}
+		}
+
+		editor := strings.Fields(editorCommand())
+		editCmd := exec.CommandContext(cmd.Context(), editor[0], append(editor[1:], path)...)
+		editCmd.Stdin = os.Stdin
+		editCmd.Stdout = os.Stdout
+		editCmd.Stderr = os.Stderr
+		if err := editCmd.Run(); err != nil {
+			return fmt.Errorf("run editor: %w", err)
+		}
+
+		edited, err := config.LoadFile(path)
+		if err != nil {
+			return fmt.Errorf("edited config is invalid: %w", err)
+		}
+		if useRepo, _ := cmd.Flags().GetBool(repoFlagName); useRepo {
+			if err := config.CheckRepoLayer(edited); err != nil {
+				return fmt.Errorf("edited config is invalid: %w", err)
+			}
+		}
+		return nil
+	},
+}
+
+// editorCommand picks the user's editor the same way git does
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd, configUnsetCmd, configPathCmd, configEditCmd)
+
+	configCmd.PersistentFlags().Bool(repoFlagName, false, "use the repository .vibecheck.yaml instead of the global file")
+	configListCmd.Flags().Bool(globalFlagName, false, "read only the global file instead of the effective configuration")
+	configGetCmd.Flags().Bool(globalFlagName, false, "read only the global file instead of the effective configuration")
+	configListCmd.Flags().Bool(originFlagName, false, "explain which layer each effective value came from")
+	configListCmd.Flags().Bool(jsonFlagName, false, "print the configuration as JSON")
+	configGetCmd.Flags().Bool(jsonFlagName, false, "print the value as JSON")
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := editorCommand(); got != "vi" {
+		t.Errorf("editorCommand() = %q, want vi", got)
+	}
+
+	t.Setenv("EDITOR", "nano")
+	if got := editorCommand(); got != "nano" {
+		t.Errorf("editorCommand() = %q, want nano", got)
+	}
+
+	t.Setenv("VISUAL", "code --wait")
+	if got := editorCommand(); got != "code --wait" {
+		t.Errorf("editorCommand() = %q, want code --wait", got)
+	}
+}
+
+func TestConfigSubcommands(t *testing.T) {
+	want := map[string]bool{"list": false, "get": false, "set": false, "unset": false, "edit": false, "path": false}
+	for _, c := range configCmd.Commands() {
+		if _, ok := want[c.Name()]; ok {
+			want[c.Name()] = true
+		}
+	}
+	for name, found := range want {
+		if !found {
+			t.Errorf("config subcommand %q not registered", name)
+		}
+	}
+}
+
+func TestConfigEditRejectsUserOnlyKeys(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init error = %v: %s", err, out)
+	}
+
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	os.WriteFile(editor, []byte("#!/bin/sh\nprintf 'redact:\\n  allow: [\".*\"]\\n' > \"$1\"\n"), 0755)
+	t.Setenv("VISUAL", editor)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.Flags().Bool(repoFlagName, true, "")
+	if err := configEditCmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), "redact.allow cannot be set") {
+		t.Errorf("config edit --repo error = %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/spf13/cobra"
+)
+
+const (
+	diffContextFlagName     = "diff-context"
+	functionContextFlagName = "function-context"
+	whitespaceFlagName      = "whitespace"
+	renamesFlagName         = "renames"
+	copiesFlagName          = "copies"
+	diffAlgorithmFlagName   = "diff-algorithm"
+	submoduleFlagName       = "submodule"
+	statOnlyFlagName        = "stat-only"
+)
+
+// addDiffFlags registers the flags that tune the staged diff on cmd
+func addDiffFlags(cmd *cobra.Command) {
+	cmd.Flags().Int(diffContextFlagName, 0, "used to set the number of context lines around each change (default 3)")
+	cmd.Flags().Bool(functionContextFlagName, false, "used to include the whole function around each change")
+	cmd.Flags().String(whitespaceFlagName, "", "used to ignore whitespace changes: all, change, eol or blank-lines")
+	cmd.Flags().String(renamesFlagName, "", "used to turn rename detection off or set its threshold, e.g. 50%")
+	cmd.Flags().String(copiesFlagName, "", "used to detect copies: on, harder, or a threshold such as 75%")
+	cmd.Flags().String(diffAlgorithmFlagName, "", fmt.Sprintf("used to select the diff algorithm: %s", strings.Join(git.DiffAlgorithms, ", ")))
+	cmd.Flags().String(submoduleFlagName, "", fmt.Sprintf("used to select how submodule changes are shown: %s", strings.Join(git.SubmoduleFormats, ", ")))
+	cmd.Flags().Bool(statOnlyFlagName, false, "used to send only a diffstat to the provider, never the code")
+}
+
+// threshold matches a similarity such as "50%" or "50"
+var threshold = regexp.MustCompile(`^\d{1,3}%?$`)
+
+// diffOptions validates the diff settings and turns them into git options
+func diffOptions(cfg config.DiffConfig) (git.DiffOptions, error) {
+	opts := git.DiffOptions{
+		Context:         cfg.Context,
+		FunctionContext: cfg.FunctionContext,
+		Algorithm:       cfg.Algorithm,
+		Submodule:       cfg.Submodule,
+		StatOnly:        cfg.StatOnly,
+	}
+	if cfg.Context != nil && *cfg.Context < 0 {
+		return opts, fmt.Errorf("diff.context: must not be negative, got %d", *cfg.Context)
+	}
+
+	switch cfg.Whitespace {
+	case "":
+	case "all":
+		opts.IgnoreWhitespace = true
+	case "change":
+		opts.IgnoreSpaceChange = true
+	case "eol":
+		opts.IgnoreSpaceAtEOL = true
+	case "blank-lines":
+		opts.IgnoreBlankLines = true
+	default:
+		return opts, fmt.Errorf("diff.whitespace: unknown mode %q, want all, change, eol or blank-lines", cfg.Whitespace)
+	}
+
+	switch {
+	case cfg.Renames == "":
+	case cfg.Renames == "off":
+		opts.NoRenames = true
+	case threshold.MatchString(cfg.Renames):
+		opts.RenameThreshold = cfg.Renames
+	default:
+		return opts, fmt.Errorf("diff.renames: want off or a threshold such as 50%%, got %q", cfg.Renames)
+	}
+
+	switch {
+	case cfg.Copies == "":
+	case cfg.Copies == "on":
+		opts.FindCopies = true
+	case cfg.Copies == "harder":
+		opts.FindCopies, opts.FindCopiesHarder = true, true
+	case threshold.MatchString(cfg.Copies):
+		opts.FindCopies, opts.CopyThreshold = true, cfg.Copies
+	default:
+		return opts, fmt.Errorf("diff.copies: want on, harder or a threshold such as 75%%, got %q", cfg.Copies)
+	}
+
+	if cfg.Algorithm != "" && !slices.Contains(git.DiffAlgorithms, cfg.Algorithm) {
+		return opts, fmt.Errorf("diff.algorithm: unknown algorithm %q, want one of %s", cfg.Algorithm, strings.Join(git.DiffAlgorithms, ", "))
+	}
+	if cfg.Submodule != "" && !slices.Contains(git.SubmoduleFormats, cfg.Submodule) {
+		return opts, fmt.Errorf("diff.submodule: unknown format %q, want one of %s", cfg.Submodule, strings.Join(git.SubmoduleFormats, ", "))
+	}
+	return opts, nil
+}
+
+// keepingWhitespace produces a diff with opts, and again without the
+// whitespace options when those hide every change, so that a formatting-only
+// change is still found and described rather than reported as nothing staged
+func keepingWhitespace(ctx context.Context, opts git.DiffOptions, diff func(context.Context, git.DiffOptions) (string, error)) (string, error) {
+	out, err := diff(ctx, opts)
+	if err != nil || strings.TrimSpace(out) != "" {
+		return out, err
+	}
+	if !opts.IgnoreWhitespace && !opts.IgnoreSpaceChange && !opts.IgnoreSpaceAtEOL && !opts.IgnoreBlankLines {
+		return out, nil
+	}
+	opts.IgnoreWhitespace, opts.IgnoreSpaceChange, opts.IgnoreSpaceAtEOL, opts.IgnoreBlankLines = false, false, false, false
+	return diff(ctx, opts)
+}
+
+// stagedDiff returns the staged changes since base with opts, keeping
+// whitespace-only changes
+func stagedDiff(ctx context.Context, base string, opts git.DiffOptions, pathspecs ...string) (string, error) {
+	return keepingWhitespace(ctx, opts, func(ctx context.Context, opts git.DiffOptions) (string, error) {
+		return git.StagedDiffSince(ctx, base, opts, pathspecs...)
+	})
+}
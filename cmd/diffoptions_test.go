@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+)
+
+func TestDiffOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.DiffConfig
+		want    git.DiffOptions
+		wantErr bool
+	}{
+		{name: "defaults", cfg: config.DiffConfig{}, want: git.DiffOptions{}},
+		{
+			name: "all settings",
+			cfg: config.DiffConfig{
+				Context: intPtr(10), FunctionContext: true, Whitespace: "change", Renames: "40%",
+				Copies: "harder", Algorithm: "histogram", Submodule: "log", StatOnly: true,
+			},
+			want: git.DiffOptions{
+				Context: intPtr(10), FunctionContext: true, IgnoreSpaceChange: true, RenameThreshold: "40%",
+				FindCopies: true, FindCopiesHarder: true, Algorithm: "histogram", Submodule: "log", StatOnly: true,
+			},
+		},
+		{name: "renames off", cfg: config.DiffConfig{Renames: "off"}, want: git.DiffOptions{NoRenames: true}},
+		{name: "copy threshold", cfg: config.DiffConfig{Copies: "75%"}, want: git.DiffOptions{FindCopies: true, CopyThreshold: "75%"}},
+		{name: "ignore all whitespace", cfg: config.DiffConfig{Whitespace: "all"}, want: git.DiffOptions{IgnoreWhitespace: true}},
+		{name: "no context", cfg: config.DiffConfig{Context: intPtr(0)}, want: git.DiffOptions{Context: intPtr(0)}},
+		{name: "negative context", cfg: config.DiffConfig{Context: intPtr(-1)}, wantErr: true},
+		{name: "unknown whitespace", cfg: config.DiffConfig{Whitespace: "tabs"}, wantErr: true},
+		{name: "bad rename threshold", cfg: config.DiffConfig{Renames: "half"}, wantErr: true},
+		{name: "unknown algorithm", cfg: config.DiffConfig{Algorithm: "fast"}, wantErr: true},
+		{name: "unknown submodule format", cfg: config.DiffConfig{Submodule: "full"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := diffOptions(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("diffOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStagedDiffKeepsWhitespace(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	os.WriteFile("a.go", []byte("package a\nvar x = 1\n"), 0644)
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	ctx := context.Background()
+	opts := git.DiffOptions{IgnoreWhitespace: true}
+	os.WriteFile("a.go", []byte("package a\nvar  x = 1\n"), 0644)
+	run("add", ".")
+	diff, err := stagedDiff(ctx, "", opts)
+	if err != nil || !strings.Contains(diff, "+var  x = 1") {
+		t.Errorf("stagedDiff(formatting only) = %q, %v", diff, err)
+	}
+
+	os.WriteFile("a.go", []byte("package a\nvar  x = 2\n"), 0644)
+	run("add", ".")
+	diff, err = stagedDiff(ctx, "", opts)
+	if err != nil || !strings.Contains(diff, "+var  x = 2") {
+		t.Errorf("stagedDiff(code) = %q, %v", diff, err)
+	}
+
+	run("reset", "-q", "--hard")
+	if diff, err := stagedDiff(ctx, "", opts); diff != "" || err != nil {
+		t.Errorf("stagedDiff(nothing staged) = %q, %v", diff, err)
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,280 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/fixup"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/patch"
+	"github.com/rshdhere/vibecheck/internal/ui/notify"
+	"github.com/spf13/cobra"
+)
+
+// fixupCandidates blames the pre-image lines of the staged hunks at HEAD and
+// ranks the commits of branch, newest first, that last changed them. Lines
+// last changed before the branch are not counted.
+func fixupCandidates(ctx context.Context, hunks []patch.Hunk, branch []git.Commit) ([]fixup.Candidate, error) {
+	onBranch := make(map[string]git.Commit, len(branch))
+	order := make([]string, len(branch))
+	for i, c := range branch {
+		onBranch[c.Hash] = c
+		order[i] = c.Hash
+	}
+
+	lines := make(map[string][]int)
+	var paths []string
+	for _, h := range hunks {
+		touched := fixup.Lines(h)
+		if len(touched) == 0 {
+			continue
+		}
+		if _, ok := lines[h.Path]; !ok {
+			paths = append(paths, h.Path)
+		}
+		lines[h.Path] = append(lines[h.Path], touched...)
+	}
+
+	votes := make(map[string]int)
+	for _, path := range paths {
+		// Renamed files are staged under their new path, which HEAD lacks
+		if _, ok, err := git.FileAt(ctx, "HEAD", path); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		blamed, err := git.Blame(ctx, "HEAD", path, lines[path])
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range blamed {
+			if _, ok := onBranch[hash]; ok {
+				votes[hash]++
+			}
+		}
+	}
+
+	candidates := fixup.Rank(votes, order)
+	for i := range candidates {
+		candidates[i].Subject = onBranch[candidates[i].Hash].Subject
+	}
+	return candidates, nil
+}
+
+// writeCandidates lists the candidates, numbered from 1
+func writeCandidates(w io.Writer, candidates []fixup.Candidate) {
+	for i, c := range candidates {
+		lines := "lines"
+		if c.Lines == 1 {
+			lines = "line"
+		}
+		fmt.Fprintf(w, "  %d. %s %s (%d %s)\n", i+1, shortHash(c.Hash), c.Subject, c.Lines, lines)
+	}
+}
+
+// askChoice asks question and reads a number from 1 to n. An empty answer
+// picks the first; anything else returns -1.
+func askChoice(in io.Reader, out io.Writer, question string, n int) (int, error) {
+	fmt.Fprintf(out, "%s [1] ", question)
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return -1, err
+	}
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		return 0, nil
+	}
+	choice, err := strconv.Atoi(answer)
+	if err != nil || choice < 1 || choice > n {
+		return -1, nil
+	}
+	return choice - 1, nil
+}
+
+var fixupCmd = &cobra.Command{
+	Use:   "fixup",
+	Short: "Commit the staged changes as a fixup! for the commit they fix",
+	Long: `Find the commit on the current branch that the staged changes fix, and commit them as a "fixup!" for it, ready to be folded in with git rebase -i --autosquash.
+
+The lines the staged hunks change or insert next to are blamed at HEAD, and the commits since the merge base with the base branch are ranked by how many of those lines they last changed. When blame is split between commits, the provider is asked to break the tie from the staged diff. The ranking is shown so the target can be confirmed or another one picked.
+
+With --amend an "amend!" commit is made instead, which also lets you reword the target's message.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		base, err := cmd.Flags().GetString(baseFlagName)
+		if err != nil {
+			return fmt.Errorf("get string base flag: %w", err)
+		}
+		if base == "" {
+			base = cfg.PR.Base
+		}
+		if base == "" {
+			base = defaultBase
+		}
+		amend, err := cmd.Flags().GetBool(amendFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool amend flag: %w", err)
+		}
+		dry, err := cmd.Flags().GetBool(dryRunFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool dry-run flag: %w", err)
+		}
+		yes, err := cmd.Flags().GetBool(yesFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool yes flag: %w", err)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+		if !interactive(cmd) {
+			notify.SetPlain(true)
+		}
+		if !yes && !dry && !isTerminal(os.Stdin) {
+			return fmt.Errorf("pass --%s to commit to the best-ranked commit without confirming", yesFlagName)
+		}
+
+		if _, err := git.RevParse(ctx, "HEAD"); err != nil {
+			return errors.New("fixup needs commits to fix; make the first commit with vibecheck commit")
+		}
+		staged, err := git.StagedDiff(ctx)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(staged) == "" {
+			notify.ShowStageReminder()
+			return reportedError(cmd, exitNothingStaged, errors.New("nothing is staged"))
+		}
+
+		if base, err = resolveBase(ctx, base); err != nil {
+			return err
+		}
+		mergeBase, err := git.MergeBase(ctx, base, "HEAD")
+		if err != nil {
+			return err
+		}
+		branch, err := git.Log(ctx, mergeBase+"..HEAD")
+		if err != nil {
+			return err
+		}
+		if len(branch) == 0 {
+			return fmt.Errorf("the branch has no commits since %s to fix up", base)
+		}
+		candidates, err := fixupCandidates(ctx, patch.Hunks(patch.Parse(staged)), branch)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			return fmt.Errorf("none of the staged lines were last changed by a commit since %s; commit them with vibecheck commit", base)
+		}
+
+		out := cmd.ErrOrStderr()
+		if fixup.Ambiguous(candidates) {
+			choice, err := breakFixupTie(cmd, cfg, staged, candidates, additionalPrompt)
+			if err != nil {
+				return err
+			}
+			if choice > 0 {
+				chosen := candidates[choice]
+				copy(candidates[1:choice+1], candidates[:choice])
+				candidates[0] = chosen
+			}
+		}
+		fmt.Fprintln(out, "The staged lines were last changed by:")
+		writeCandidates(out, candidates)
+
+		target := candidates[0]
+		if dry {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", target.Hash, target.Subject)
+			return nil
+		}
+		if !yes {
+			choice, err := askChoice(cmd.InOrStdin(), out, "Fix up which commit?", len(candidates))
+			if err != nil {
+				return err
+			}
+			if choice < 0 {
+				fmt.Fprintln(out, "Fixup cancelled; nothing was committed")
+				return nil
+			}
+			target = candidates[choice]
+		}
+
+		opts := git.CommitOptions{Fixup: target.Hash, NoEdit: yes}
+		kind := "a fixup!"
+		if amend {
+			opts.Fixup = "amend:" + target.Hash
+			kind = "an amend!"
+		}
+		if err := git.CommitWMessage(ctx, "", opts); err != nil {
+			return fmt.Errorf("commit fixup: %w", err)
+		}
+		fmt.Fprintf(out, "Committed %s for %s; fold it in with git rebase -i --autosquash %s\n", kind, shortHash(target.Hash), base)
+		return nil
+	},
+}
+
+// breakFixupTie asks the provider which of the candidates the staged diff
+// fixes, returning its index, or -1 if the answer names none of them
+func breakFixupTie(cmd *cobra.Command, cfg *config.Resolved, diff string, candidates []fixup.Candidate, additionalPrompt string) (int, error) {
+	ctx := cmd.Context()
+	a, err := analyzeStaged(ctx, cfg, diff, nil, "")
+	if err != nil {
+		return -1, err
+	}
+	provider, err := llm.GetProvider(cfg.DefaultProvider)
+	if err != nil {
+		return -1, err
+	}
+	providerDiff, listing, err := redactRequest(cmd, cfg.Config, provider, cfg.DefaultProvider, a.diff, fixup.Describe(candidates), requestLabel{diff: "the staged changes", source: "candidates", action: "Commit"})
+	if err != nil {
+		return -1, err
+	}
+	req := &messageRequest{
+		provider:     provider,
+		providerName: cfg.DefaultProvider,
+		model:        cfg.Model,
+		diff:         "Candidate commits:\n" + listing + "\nStaged diff:\n" + providerDiff,
+		prompt:       buildPromptContext(cfg.Config, a, additionalPrompt),
+	}
+
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithColor("cyan"), spinner.WithWriterFile(os.Stderr))
+	s.Suffix = fmt.Sprintf(" Choosing between %s...", commitCount(len(candidates)))
+	s.Start()
+	response, _, err := req.run(llm.WithSystemPrompt(ctx, fixup.SystemPrompt))
+	s.Stop()
+	if err != nil {
+		return -1, providerError(cmd, cfg.DefaultProvider, err)
+	}
+	return fixup.Choose(response, candidates), nil
+}
+
+func init() {
+	rootCmd.AddCommand(fixupCmd)
+	fixupCmd.Flags().String(baseFlagName, "", fmt.Sprintf("used to select the branch the current one started from (default %q, or pr.base)", defaultBase))
+	fixupCmd.Flags().Bool(amendFlagName, false, "used to create an amend! commit, which also rewords the target")
+	fixupCmd.Flags().Bool(dryRunFlagName, false, "used to print the best-ranked commit without committing")
+	fixupCmd.Flags().BoolP(yesFlagName, "y", false, "used to commit to the best-ranked commit without confirming")
+	fixupCmd.Flags().String(promptFlagName, "", "used to provide additional context to llm")
+	fixupCmd.Flags().String(providerFlagName, "", "used to select the ai-provider that breaks ties between commits")
+	fixupCmd.Flags().String(modelFlagName, "", "used to override the model of the selected provider")
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/patch"
+)
+
+func TestFixupCandidates(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name string, lines ...string) {
+		os.WriteFile(name, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	}
+	run("init", "-q", "-b", "main")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+
+	write("a.txt", "1", "2", "3", "4", "5", "6")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	run("checkout", "-q", "-b", "topic")
+	write("a.txt", "1", "two", "three", "4", "5", "6")
+	run("commit", "-q", "-am", "feat: spell out two and three")
+	feat := run("rev-parse", "HEAD")
+	write("a.txt", "1", "two", "three", "4", "5", "six")
+	write("b.txt", "b")
+	run("add", ".")
+	run("commit", "-q", "-m", "fix: spell out six")
+	fix := run("rev-parse", "HEAD")
+
+	ctx := context.Background()
+	branch, err := git.Log(ctx, "main..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both feat lines and one line from before the branch change; the new
+	// file has no history
+	write("a.txt", "one", "2", "3", "4", "5", "six")
+	write("c.txt", "c")
+	run("add", ".")
+	staged, err := git.StagedDiff(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates, err := fixupCandidates(ctx, patch.Hunks(patch.Parse(staged)), branch)
+	if err != nil {
+		t.Fatalf("fixupCandidates() error = %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Hash != feat || candidates[0].Lines != 2 || candidates[0].Subject != "feat: spell out two and three" {
+		t.Errorf("fixupCandidates() = %+v, want only %s with 2 lines", candidates, feat)
+	}
+
+	// A tie goes to the newest commit
+	write("a.txt", "1", "two", "3", "4", "5", "6")
+	run("add", ".")
+	run("reset", "-q", "c.txt")
+	staged, err = git.StagedDiff(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates, err = fixupCandidates(ctx, patch.Hunks(patch.Parse(staged)), branch)
+	if err != nil {
+		t.Fatalf("fixupCandidates() error = %v", err)
+	}
+	if len(candidates) != 2 || candidates[0].Hash != fix || candidates[0].Lines != 1 || candidates[1].Hash != feat {
+		t.Errorf("fixupCandidates() = %+v, want %s then %s", candidates, fix, feat)
+	}
+}
+
+func TestAskChoice(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   int
+	}{
+		{"\n", 0},
+		{"2\n", 1},
+		{" 3 \n", 2},
+		{"4\n", -1},
+		{"q\n", -1},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got, err := askChoice(strings.NewReader(tt.answer), &out, "Fix up which commit?", 3)
+		if err != nil || got != tt.want {
+			t.Errorf("askChoice(%q) = %d, %v, want %d", tt.answer, got, err, tt.want)
+		}
+		if out.String() != "Fix up which commit? [1] " {
+			t.Errorf("askChoice() asked %q", out.String())
+		}
+	}
+}
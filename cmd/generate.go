@@ -0,0 +1,142 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// errNoDiff is returned when generate is given nothing to describe
+var errNoDiff = errors.New("no diff to describe")
+
+// readDiff reads the diff generate describes: stdin for "-" or no argument, a
+// patch file when arg names one, and otherwise a revision or revision range
+func readDiff(ctx context.Context, in io.Reader, arg string, opts git.DiffOptions) (string, diffSource, error) {
+	if arg == "" || arg == "-" {
+		if arg == "" && in == os.Stdin && isTerminal(os.Stdin) {
+			return "", diffSource{}, fmt.Errorf("%w: pipe a diff, or pass a patch file or a revision range", errNoDiff)
+		}
+		b, err := io.ReadAll(in)
+		if err != nil {
+			return "", diffSource{}, fmt.Errorf("read stdin: %w", err)
+		}
+		return string(b), diffSource{detached: true}, nil
+	}
+
+	if info, err := os.Stat(arg); err == nil && info.Mode().IsRegular() {
+		b, err := os.ReadFile(arg)
+		if err != nil {
+			return "", diffSource{}, err
+		}
+		return string(b), diffSource{detached: true}, nil
+	}
+
+	src, err := revisionSource(ctx, arg)
+	if err != nil {
+		return "", diffSource{}, err
+	}
+	diff, err := keepingWhitespace(ctx, opts, func(ctx context.Context, opts git.DiffOptions) (string, error) {
+		return git.RevisionDiff(ctx, opts, arg)
+	})
+	if err != nil {
+		return "", diffSource{}, err
+	}
+	return diff, src, nil
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate [<rev-range> | <patch-file> | -]",
+	Short: "Print a commit message for a diff without committing",
+	Long: `Print a commit message for an arbitrary diff instead of the staged changes. The diff is read from stdin, a patch file such as one from git format-patch or email, a commit, or a revision range like main..topic.
+
+  git diff | vibecheck generate
+  vibecheck generate 0001-fix-typo.patch
+  vibecheck generate main..topic`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		opts, err := diffOptions(cfg.Diff)
+		if err != nil {
+			return err
+		}
+		patchOpts := opts
+		patchOpts.StatOnly = false
+
+		arg := ""
+		if len(args) == 1 {
+			arg = args[0]
+		}
+		diff, src, err := readDiff(cmd.Context(), cmd.InOrStdin(), arg, patchOpts)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(diff) == "" {
+			return errNoDiff
+		}
+
+		issueKey, err := cmd.Flags().GetString(issueFlagName)
+		if err != nil {
+			return fmt.Errorf("get string issue flag: %w", err)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+
+		a, err := analyzeDiff(cmd.Context(), cfg, diff, src, issueKey)
+		if err != nil {
+			return err
+		}
+		if err := a.requireIssue(cfg); err != nil {
+			return err
+		}
+		if opts.StatOnly {
+			a.statOnly = true
+			if a.diff, err = git.PatchStat(cmd.Context(), diff); err != nil {
+				return err
+			}
+		}
+
+		message := a.localMessage
+		if message == "" {
+			message, _, err = generateMessage(cmd, cfg.Config, a, additionalPrompt)
+			if err != nil {
+				return err
+			}
+		}
+		message, err = a.finalize(message)
+		if err != nil {
+			return err
+		}
+
+		isBreaking, err := confirmBreaking(cmd, a.breaking)
+		if err != nil {
+			return fmt.Errorf("confirm breaking change: %w", err)
+		}
+		if isBreaking {
+			message = markBreaking(message, a.breaking)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSpace(message))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	addMessageFlags(generateCmd)
+	addDiffFlags(generateCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+)
+
+func TestReadDiff(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	os.WriteFile("a.txt", []byte("one\n"), 0644)
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	os.WriteFile("a.txt", []byte("two\n"), 0644)
+	run("commit", "-q", "-am", "second")
+
+	ctx := context.Background()
+	const patch = "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+	diff, src, err := readDiff(ctx, strings.NewReader(patch), "-", git.DiffOptions{})
+	if err != nil || diff != patch || !src.detached {
+		t.Errorf("readDiff(-) = %q, %+v, %v", diff, src, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "0001-change.patch")
+	os.WriteFile(file, []byte("Subject: [PATCH] change\n---\n"+patch), 0644)
+	diff, src, err = readDiff(ctx, nil, file, git.DiffOptions{})
+	if err != nil || !strings.Contains(diff, "+b") || !src.detached {
+		t.Errorf("readDiff(file) = %q, %+v, %v", diff, src, err)
+	}
+
+	diff, src, err = readDiff(ctx, nil, "HEAD~1..HEAD", git.DiffOptions{})
+	if err != nil || !strings.Contains(diff, "+two") {
+		t.Fatalf("readDiff(range) = %q, %v", diff, err)
+	}
+	if src.detached || src.before != "HEAD~1" || src.after != "HEAD" {
+		t.Errorf("readDiff(range) source = %+v", src)
+	}
+
+	parent, err := git.RevParse(ctx, "HEAD^")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, src, err = readDiff(ctx, nil, "HEAD", git.DiffOptions{})
+	if err != nil || !strings.Contains(diff, "+two") || src.before != parent {
+		t.Errorf("readDiff(commit) = %q, %+v, %v", diff, src, err)
+	}
+
+	if _, _, err := readDiff(ctx, nil, "no-such-rev", git.DiffOptions{}); err == nil {
+		t.Error("readDiff(no-such-rev) error = nil")
+	}
+	if diff, _, err := readDiff(ctx, strings.NewReader(""), "", git.DiffOptions{}); diff != "" || err != nil {
+		t.Errorf("readDiff(empty stdin) = %q, %v", diff, err)
+	}
+}
@@ -0,0 +1,274 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/hook"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+	"github.com/rshdhere/vibecheck/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// hookPlan is what the prepare-commit-msg hook does for one commit
+type hookPlan struct {
+	// fill is set when the hook writes a message at all
+	fill bool
+	// replace drops the message git prepared instead of keeping it below
+	replace bool
+	// base is the revision the index is described against, HEAD when empty
+	base string
+}
+
+// planHook decides from the arguments git passed whether to write a message.
+// Messages given with -m, -F or a filled-in template are always kept; merges,
+// squashes and amends only get a message when enabled in the hook config.
+func planHook(ctx context.Context, cfg config.HookConfig, source hook.Source, sha, content string) (hookPlan, error) {
+	switch source {
+	case hook.SourceNone, hook.SourceTemplate:
+		// A hook chained before this one may already have written a message
+		return hookPlan{fill: !hook.HasMessage(content)}, nil
+	case hook.SourceMerge:
+		return hookPlan{fill: cfg.Merge, replace: true}, nil
+	case hook.SourceSquash:
+		return hookPlan{fill: cfg.Squash, replace: true}, nil
+	case hook.SourceCommit:
+		if !cfg.Amend {
+			return hookPlan{}, nil
+		}
+		// -c and -C reuse the message of another commit, only --amend names HEAD
+		head, err := git.RevParse(ctx, "HEAD")
+		if err != nil {
+			return hookPlan{}, err
+		}
+		if target, err := git.RevParse(ctx, sha); err != nil || target != head {
+			return hookPlan{}, nil
+		}
+		base, err := git.Parent(ctx, "HEAD")
+		if err != nil {
+			return hookPlan{}, err
+		}
+		return hookPlan{fill: true, replace: true, base: base}, nil
+	default:
+		return hookPlan{}, nil
+	}
+}
+
+// runHook writes a message into the file git passed to prepare-commit-msg
+func runHook(cmd *cobra.Command, file string, source hook.Source, sha string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	cfg, err := resolveConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("resolve config: %w", err)
+	}
+	plan, err := planHook(cmd.Context(), cfg.Hook, source, sha, string(content))
+	if err != nil || !plan.fill {
+		return err
+	}
+
+	opts, err := diffOptions(cfg.Diff)
+	if err != nil {
+		return err
+	}
+	patchOpts := opts
+	patchOpts.StatOnly = false
+	diff, err := stagedDiff(cmd.Context(), plan.base, patchOpts)
+	if err != nil {
+		return fmt.Errorf("staged changes: %w", err)
+	}
+	if strings.TrimSpace(diff) == "" {
+		return nil
+	}
+
+	a, err := analyzeDiff(cmd.Context(), cfg, diff, stagedSource(plan.base, nil), "")
+	if err != nil {
+		return err
+	}
+	if err := a.requireIssue(cfg); err != nil {
+		return err
+	}
+	if opts.StatOnly {
+		a.statOnly = true
+		if a.diff, err = stagedDiff(cmd.Context(), plan.base, opts); err != nil {
+			return fmt.Errorf("staged diffstat: %w", err)
+		}
+	}
+
+	generated := a.localMessage == ""
+	message, latency := a.localMessage, 0.0
+	if generated {
+		message, latency, err = generateMessage(cmd, cfg.Config, a, "")
+		if err != nil {
+			return err
+		}
+	}
+	if message, err = a.finalize(message); err != nil {
+		return err
+	}
+	isBreaking, err := confirmBreaking(cmd, a.breaking)
+	if err != nil {
+		return fmt.Errorf("confirm breaking change: %w", err)
+	}
+	if isBreaking {
+		message = markBreaking(message, a.breaking)
+	}
+
+	var topts trailerOptions
+	if generated && cfg.Provenance.Trailer {
+		record := provenance.Record{
+			Tool:          provenance.Tool,
+			Version:       version,
+			Provider:      cfg.DefaultProvider,
+			Model:         providerModel(cfg.DefaultProvider, cfg.Model),
+			Latency:       latency,
+			PromptVersion: llm.PromptVersion,
+			DiffHash:      provenance.HashDiff(a.sent),
+		}
+		topts.generatedBy = record.TrailerValue()
+	}
+	trailers, err := collectTrailers(cmd.Context(), cfg.Config, a, topts)
+	if err != nil {
+		return err
+	}
+	if message, err = git.InterpretTrailers(cmd.Context(), message, trailers); err != nil {
+		return err
+	}
+
+	out := hook.Prepend(message, string(content))
+	if plan.replace {
+		out = hook.Replace(message, string(content))
+	}
+	if err := os.WriteFile(file, []byte(out), 0644); err != nil {
+		return err
+	}
+
+	if generated {
+		// The hook runs before the commit exists, so this counts the message
+		// even if the commit is then aborted
+		commitMsg, _, _ := strings.Cut(message, "\n")
+		_ = stats.RecordCommit(cfg.DefaultProvider, latency, commitMsg)
+	}
+	return nil
+}
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage the prepare-commit-msg hook for commits made with plain git",
+	Long: `Install a prepare-commit-msg hook so that git commit, and IDEs that run it, get a vibecheck message whenever no message was given.
+
+Merges, squashes and amends keep git's message unless enabled with "vibecheck config set hook.merge true", hook.squash or hook.amend. An existing prepare-commit-msg hook is kept and runs first.`,
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the prepare-commit-msg hook in this repository",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := git.HooksDir(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("hooks directory: %w", err)
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		st, err := hook.Install(dir, executable)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Installed %s\n", st.Path)
+		if st.Chained != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "The existing hook was moved to %s and runs first\n", st.Chained)
+		}
+		return nil
+	},
+}
+
+var hookUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the prepare-commit-msg hook and restore the one it replaced",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := git.HooksDir(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("hooks directory: %w", err)
+		}
+		st, err := hook.Uninstall(dir)
+		if err != nil {
+			return err
+		}
+		if st.Foreign {
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed the vibecheck hook and restored %s\n", st.Path)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s\n", st.Path)
+		}
+		return nil
+	},
+}
+
+var hookStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the prepare-commit-msg hook is installed",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := git.HooksDir(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("hooks directory: %w", err)
+		}
+		st, err := hook.Inspect(dir)
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		switch {
+		case st.Installed:
+			fmt.Fprintf(out, "installed: %s\n", st.Path)
+		case st.Foreign:
+			fmt.Fprintf(out, "not installed: %s belongs to another tool (install keeps it and runs it first)\n", st.Path)
+		default:
+			fmt.Fprintf(out, "not installed: run \"vibecheck hook install\"\n")
+		}
+		if st.Chained != "" {
+			fmt.Fprintf(out, "chained: %s\n", st.Chained)
+		}
+		return nil
+	},
+}
+
+var hookRunCmd = &cobra.Command{
+	Use:    "run <message-file> [<source> [<sha>]]",
+	Short:  "Run as the prepare-commit-msg hook",
+	Hidden: true,
+	Args:   cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var source, sha string
+		if len(args) > 1 {
+			source = args[1]
+		}
+		if len(args) > 2 {
+			sha = args[2]
+		}
+		// A failing hook would abort the commit; leave the message to the user instead
+		if err := runHook(cmd, args[0], hook.Source(source), sha); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "vibecheck: no message generated: %v\n", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookInstallCmd, hookUninstallCmd, hookStatusCmd, hookRunCmd)
+}
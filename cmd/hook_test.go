@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/hook"
+	"github.com/spf13/cobra"
+)
+
+func TestPlanHook(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		cfg     config.HookConfig
+		source  hook.Source
+		content string
+		want    hookPlan
+	}{
+		{name: "no message", source: hook.SourceNone, want: hookPlan{fill: true}},
+		{name: "message written by another hook", source: hook.SourceNone, content: "chore: bump version\n\n# Please enter the commit message\n"},
+		{name: "verbose", source: hook.SourceNone, content: "\n# Please enter the commit message\n" + hook.Scissors + "\ndiff --git a/a b/a\n+a\n", want: hookPlan{fill: true}},
+		{name: "message given", source: hook.SourceMessage, content: "fix: typo\n"},
+		{name: "empty template", source: hook.SourceTemplate, content: "# describe the change\n", want: hookPlan{fill: true}},
+		{name: "filled template", source: hook.SourceTemplate, content: "feat: \n"},
+		{name: "merge", source: hook.SourceMerge, want: hookPlan{replace: true}},
+		{name: "merge enabled", cfg: config.HookConfig{Merge: true}, source: hook.SourceMerge, want: hookPlan{fill: true, replace: true}},
+		{name: "squash", source: hook.SourceSquash, want: hookPlan{replace: true}},
+		{name: "amend", source: hook.SourceCommit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := planHook(ctx, tt.cfg, tt.source, "", tt.content)
+			if err != nil {
+				t.Fatalf("planHook() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("planHook() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunHook(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.name", "Jane Doe")
+	git("config", "user.email", "jane@example.com")
+	os.WriteFile("util.go", []byte("package main\n"), 0644)
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+	git("mv", "util.go", "math.go")
+
+	file := ".git/COMMIT_EDITMSG"
+	prepared := "\n# Please enter the commit message for your changes.\n"
+	os.WriteFile(file, []byte(prepared), 0644)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if err := runHook(cmd, file, hook.SourceNone, ""); err != nil {
+		t.Fatalf("runHook() error = %v", err)
+	}
+	got, _ := os.ReadFile(file)
+	if want := "refactor: rename util.go to math.go\n" + prepared; string(got) != want {
+		t.Errorf("message file = %q, want %q", got, want)
+	}
+
+	os.WriteFile(file, []byte("fix: my own words\n"), 0644)
+	if err := runHook(cmd, file, hook.SourceMessage, ""); err != nil {
+		t.Fatalf("runHook(message) error = %v", err)
+	}
+	if got, _ := os.ReadFile(file); !strings.HasPrefix(string(got), "fix: my own words") {
+		t.Errorf("message file = %q, want it untouched", got)
+	}
+}
@@ -54,6 +54,7 @@ type keysModel struct {
 	state        string // "list", "input", "saving"
 	quitting     bool
 	errorMsg     string
+	profile      string
 }
 
 func (m keysModel) Init() tea.Cmd {
@@ -170,6 +171,11 @@ func (m keysModel) View() string {
 		MarginTop(1)
 
 	title := titleStyle.Render("VIBECHECK API KEYS")
+	if m.profile != "" {
+		title += "\n" + lipgloss.NewStyle().
+			Foreground(mutedColor).
+			Render(fmt.Sprintf("Profile: %s", lipgloss.NewStyle().Foreground(secondaryColor).Bold(true).Render(m.profile)))
+	}
 
 	// Error message
 	errorStyle := lipgloss.NewStyle().
@@ -427,8 +433,14 @@ func (d keyItemDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 var keysCmd = &cobra.Command{
 	Use:   "keys",
 	Short: "Interactively manage API keys for AI providers",
-	Long:  `Manage API keys for all AI providers. Keys are stored globally in ~/.vibecheck_keys.json and will be used when environment variables are not set.`,
+	Long:  `Manage API keys for all AI providers. Keys are stored globally in ~/.vibecheck_keys.json and will be used when environment variables are not set. A profile with a keys reference uses ~/.vibecheck_keys.<keys>.json instead.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Resolving the config selects the key set of the active profile
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+
 		items := loadKeyItems()
 
 		// Create list
@@ -456,6 +468,7 @@ var keysCmd = &cobra.Command{
 			items:     items,
 			textInput: ti,
 			state:     "list",
+			profile:   cfg.Profile,
 		}
 
 		p := tea.NewProgram(m)
@@ -103,11 +103,26 @@ var availableModels = []Model{
 	},
 }
 
+// providerModel returns the model a provider will use: the override when one is
+// set, the provider's default otherwise
+func providerModel(provider, override string) string {
+	if override != "" {
+		return override
+	}
+	for _, m := range availableModels {
+		if m.name == provider {
+			return m.model
+		}
+	}
+	return ""
+}
+
 type modelSelection struct {
-	list         list.Model
-	choice       string
-	quitting     bool
-	currentModel string
+	list          list.Model
+	choice        string
+	quitting      bool
+	currentModel  string
+	activeProfile string
 }
 
 func (m modelSelection) Init() tea.Cmd {
@@ -184,6 +199,14 @@ func (m modelSelection) View() string {
 			currentValueStyle.Render(m.currentModel),
 		),
 	)
+	if m.activeProfile != "" {
+		subtitle += subtitleStyle.Render(
+			fmt.Sprintf("  %s %s",
+				currentLabelStyle.Render("Profile:"),
+				currentValueStyle.Render(m.activeProfile),
+			),
+		)
+	}
 
 	// Border for the list
 	listBoxStyle := lipgloss.NewStyle().
@@ -248,8 +271,12 @@ var modelsCmd = &cobra.Command{
 			}
 		}
 
-		// Get current default
-		currentDefault := config.GetDefaultProvider()
+		// Get current default, taking the active profile into account
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		currentDefault := cfg.DefaultProvider
 
 		// Create list
 		const defaultWidth = 80
@@ -272,8 +299,9 @@ var modelsCmd = &cobra.Command{
 		}
 
 		m := modelSelection{
-			list:         l,
-			currentModel: currentDefault,
+			list:          l,
+			currentModel:  currentDefault,
+			activeProfile: cfg.Profile,
 		}
 
 		p := tea.NewProgram(m)
@@ -284,7 +312,7 @@ var modelsCmd = &cobra.Command{
 
 		if m, ok := finalModel.(modelSelection); ok {
 			if m.choice != "" && m.choice != currentDefault {
-				if err := config.SetDefaultProvider(m.choice); err != nil {
+				if err := saveDefaultProvider(cfg.Profile, m.choice); err != nil {
 					return fmt.Errorf("failed to save configuration: %w", err)
 				}
 
@@ -314,6 +342,19 @@ var modelsCmd = &cobra.Command{
 	},
 }
 
+// saveDefaultProvider stores the selection in the active profile when there is
+// one, so the choice is not shadowed by the profile's own provider
+func saveDefaultProvider(profile, provider string) error {
+	if profile == "" {
+		return config.SetDefaultProvider(provider)
+	}
+	path, err := config.GlobalPath()
+	if err != nil {
+		return err
+	}
+	return config.SetValue(path, "profiles."+profile+".default_provider", provider)
+}
+
 // Custom delegate for better styling
 type itemDelegate struct{}
 
@@ -0,0 +1,177 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/pr"
+	"github.com/rshdhere/vibecheck/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	baseFlagName       = "base"
+	outputFileFlagName = "output-file"
+
+	// defaultBase is the branch pull requests target when none is configured
+	defaultBase = "main"
+)
+
+// resolveBase returns base, or its remote-tracking branch on origin when
+// there is no local branch of that name
+func resolveBase(ctx context.Context, base string) (string, error) {
+	if _, err := git.RevParse(ctx, base); err == nil {
+		return base, nil
+	}
+	if _, err := git.RevParse(ctx, "origin/"+base); err == nil {
+		return "origin/" + base, nil
+	}
+	return "", fmt.Errorf("unknown base %q; pass --%s", base, baseFlagName)
+}
+
+// prPrompt hands the commits of the branch, the template and the local
+// analysis to the provider. Commit message instructions such as the style and
+// the allowed scopes are left out; they do not apply to a description.
+func prPrompt(cfg *config.Config, a *analysis, commits []git.Commit, template, userPrompt string) string {
+	var b prompt.Builder
+	b.Add("Commits on the branch, oldest first", commitList(commits))
+	b.Add("Description template", template)
+	if cfg.Language != "" {
+		b.Add("Language", fmt.Sprintf("Write the title and description in %s.", cfg.Language))
+	}
+	a.addChangeSections(&b)
+	if len(a.breaking) > 0 {
+		b.Add("Possible breaking changes", a.breakingList()+"\nDescribe these in the breaking changes section, with how to migrate.")
+	}
+	b.Add("", userPrompt)
+	return b.String()
+}
+
+var prCmd = &cobra.Command{
+	Use:   "pr [--base main]",
+	Short: "Write a pull request title and description for the current branch",
+	Long: `Collect the commits and the combined diff of the current branch since it left the base branch and write a pull request title and Markdown description. The first line printed is the title, followed by a blank line and the description, so the output can be passed to gh pr create or pasted into any forge.
+
+The description fills in the sections of a template: the file set with "vibecheck config set pr.template <path>", the repository's .github/pull_request_template.md, or Summary, Changes, Testing and Breaking changes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		base, err := cmd.Flags().GetString(baseFlagName)
+		if err != nil {
+			return fmt.Errorf("get string base flag: %w", err)
+		}
+		if base == "" {
+			base = cfg.PR.Base
+		}
+		if base == "" {
+			base = defaultBase
+		}
+		outputFile, err := cmd.Flags().GetString(outputFileFlagName)
+		if err != nil {
+			return fmt.Errorf("get string output-file flag: %w", err)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+		issueKey, err := cmd.Flags().GetString(issueFlagName)
+		if err != nil {
+			return fmt.Errorf("get string issue flag: %w", err)
+		}
+
+		if base, err = resolveBase(ctx, base); err != nil {
+			return err
+		}
+		mergeBase, err := git.MergeBase(ctx, base, "HEAD")
+		if err != nil {
+			return err
+		}
+		commits, err := git.Log(ctx, "--reverse", mergeBase+"..HEAD")
+		if err != nil {
+			return err
+		}
+		if len(commits) == 0 {
+			return fmt.Errorf("the branch has no commits since %s", base)
+		}
+
+		root, err := git.TopLevel(ctx)
+		if err != nil {
+			return err
+		}
+		template, err := pr.LoadTemplate(root, cfg.PR.Template)
+		if err != nil {
+			return fmt.Errorf("pull request template: %w", err)
+		}
+
+		opts, err := diffOptions(cfg.Diff)
+		if err != nil {
+			return err
+		}
+		patchOpts := opts
+		patchOpts.StatOnly = false
+		rev := mergeBase + "..HEAD"
+		diff, err := git.RevisionDiff(ctx, patchOpts, rev)
+		if err != nil {
+			return err
+		}
+		src, err := revisionSource(ctx, rev)
+		if err != nil {
+			return err
+		}
+		a, err := analyzeDiff(ctx, cfg, diff, src, issueKey)
+		if err != nil {
+			return err
+		}
+		if opts.StatOnly {
+			a.statOnly = true
+			if a.diff, err = git.PatchStat(ctx, diff); err != nil {
+				return err
+			}
+		}
+
+		// Descriptions are never written locally, and need their own instructions
+		cmd.SetContext(llm.WithSystemPrompt(ctx, pr.SystemPrompt))
+		req, err := newRequest(cmd, cfg.Config, a, prPrompt(cfg.Config, a, commits, template, additionalPrompt), prLabel)
+		if err != nil {
+			return err
+		}
+		response, _, err := runRequest(cmd, req)
+		if err != nil {
+			return err
+		}
+		description := pr.Parse(response)
+		if description.Title == "" {
+			return fmt.Errorf("the provider returned no pull request title")
+		}
+
+		if outputFile != "" {
+			if err := os.WriteFile(outputFile, []byte(description.String()), 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s\n", outputFile)
+			return nil
+		}
+		fmt.Fprint(cmd.OutOrStdout(), description.String())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+	addMessageFlags(prCmd)
+	addDiffFlags(prCmd)
+	prCmd.Flags().String(baseFlagName, "", fmt.Sprintf("used to select the branch the pull request targets (default %q, or pr.base)", defaultBase))
+	prCmd.Flags().StringP(outputFileFlagName, "o", "", "used to write the title and description to a file instead of stdout")
+}
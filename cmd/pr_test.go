@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/breaking"
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+)
+
+func TestPRPrompt(t *testing.T) {
+	commits := []git.Commit{{Message: "wip"}, {Message: "feat: add pr"}}
+	got := prPrompt(&config.Config{}, &analysis{}, commits, "## Summary\n", "mention the ticket")
+	want := "Commits on the branch, oldest first:\n- wip\n- feat: add pr\n\nDescription template:\n## Summary\n\nmention the ticket"
+	if got != want {
+		t.Errorf("prPrompt() = %q, want %q", got, want)
+	}
+	if strings.Contains(prPrompt(&config.Config{}, &analysis{}, commits, "", ""), "Description template") {
+		t.Error("prPrompt() without a template has a template section")
+	}
+
+	// Commit message instructions do not apply to a description
+	cfg := &config.Config{Style: "terse", Language: "German", Instructions: "Use the ticket as the scope"}
+	a := &analysis{
+		allowedScopes:  []string{"cli"},
+		dominantScopes: []string{"cli"},
+		breaking:       []breaking.Finding{{Kind: breaking.KindRemoved, File: "a.go", Name: "Run"}},
+	}
+	got = prPrompt(cfg, a, commits, "", "")
+	for _, unwanted := range []string{"terse", "Allowed scopes", "Suggested scope", "BREAKING CHANGE footer", "ticket as the scope"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("prPrompt() = %q, has %q", got, unwanted)
+		}
+	}
+	for _, want := range []string{"Write the title and description in German.", "Possible breaking changes", "breaking changes section"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prPrompt() = %q, missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+	"github.com/spf13/cobra"
+)
+
+const maxCountFlagName = "max-count"
+
+// provenanceEntry is one commit in the provenance report
+type provenanceEntry struct {
+	Hash        string             `json:"hash"`
+	Subject     string             `json:"subject"`
+	AIAssisted  bool               `json:"ai_assisted"`
+	Trailer     bool               `json:"trailer"`
+	Note        bool               `json:"note"`
+	Record      *provenance.Record `json:"record,omitempty"`
+	TrailerText string             `json:"generated_by,omitempty"`
+}
+
+// addProvenanceNote attaches the record to rev under refs/notes/vibecheck
+func addProvenanceNote(ctx context.Context, rev string, record provenance.Record) error {
+	note, err := record.Note()
+	if err != nil {
+		return err
+	}
+	return git.AddNote(ctx, provenance.NotesRef, rev, note)
+}
+
+// inspectProvenance looks for a vibecheck Generated-by trailer and note on each commit
+func inspectProvenance(ctx context.Context, commits []git.Commit) ([]provenanceEntry, error) {
+	noted, err := git.NotedCommits(ctx, provenance.NotesRef)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]provenanceEntry, 0, len(commits))
+	for _, c := range commits {
+		e := provenanceEntry{Hash: c.Hash, Subject: c.Subject}
+
+		trailers, err := git.ParseMessageTrailers(ctx, c.Message)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range trailers {
+			if t.Token != provenance.TrailerToken {
+				continue
+			}
+			if r, ok := provenance.ParseTrailer(t.Value); ok {
+				e.Trailer = true
+				e.TrailerText = t.Value
+				e.Record = &r
+			}
+		}
+
+		if noted[c.Hash] {
+			note, ok, err := git.ReadNote(ctx, provenance.NotesRef, c.Hash)
+			if err != nil {
+				return nil, err
+			}
+			// Notes that are not ours are ignored rather than reported as errors
+			if r, err := provenance.ParseNote(note); ok && err == nil {
+				e.Note = true
+				e.Record = &r
+			}
+		}
+
+		e.AIAssisted = e.Trailer || e.Note
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+var provenanceCmd = &cobra.Command{
+	Use:   "provenance [<rev-range>]",
+	Short: "Report which commits had their message generated by vibecheck",
+	Long: `List the commits in a revision range (HEAD by default) and report which of them were AI-assisted, using the Generated-by trailer and the notes under refs/notes/vibecheck.
+
+Enable the records with "vibecheck config set provenance.trailer true" and "vibecheck config set provenance.notes true". Notes are not pushed by default; share them with "git push origin refs/notes/vibecheck".`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rev := "HEAD"
+		if len(args) == 1 {
+			rev = args[0]
+		}
+		maxCount, err := cmd.Flags().GetInt(maxCountFlagName)
+		if err != nil {
+			return fmt.Errorf("get int max-count flag: %w", err)
+		}
+		asJSON, err := cmd.Flags().GetBool(jsonFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool json flag: %w", err)
+		}
+
+		revs := []string{rev}
+		if maxCount > 0 {
+			revs = append(revs, fmt.Sprintf("--max-count=%d", maxCount))
+		}
+		commits, err := git.Log(cmd.Context(), revs...)
+		if err != nil {
+			return err
+		}
+		entries, err := inspectProvenance(cmd.Context(), commits)
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			return writeJSON(cmd.OutOrStdout(), entries)
+		}
+
+		assisted := 0
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			source, model := "-", "-"
+			switch {
+			case e.Trailer && e.Note:
+				source = "trailer+note"
+			case e.Trailer:
+				source = "trailer"
+			case e.Note:
+				source = "note"
+			}
+			if e.Record != nil {
+				model = e.Record.Provider + "/" + e.Record.Model
+				assisted++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Hash[:7], source, model, e.Subject)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "\n%d of %d commits AI-assisted\n", assisted, len(entries))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(provenanceCmd)
+
+	provenanceCmd.Flags().IntP(maxCountFlagName, "n", 0, "used to limit the number of commits inspected")
+	provenanceCmd.Flags().Bool(jsonFlagName, false, "used to print the report as JSON")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+)
+
+func TestInspectProvenance(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Jane Doe"},
+		{"config", "user.email", "jane@example.com"},
+		{"commit", "-q", "--allow-empty", "-m", "chore: written by hand"},
+		{"commit", "-q", "--allow-empty", "-m", "feat: add refunds\n\nGenerated-by: vibecheck/1.0.0 (groq/llama-3.3-70b-versatile)"},
+		{"commit", "-q", "--allow-empty", "-m", "fix: noted only"},
+	} {
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatalf("git %v error = %v", args, err)
+		}
+	}
+
+	ctx := context.Background()
+	record := provenance.Record{Tool: provenance.Tool, Version: "1.0.0", Provider: "ollama", Model: "gpt-oss:20b"}
+	if err := addProvenanceNote(ctx, "HEAD", record); err != nil {
+		t.Fatalf("addProvenanceNote() error = %v", err)
+	}
+
+	commits, err := git.Log(ctx, "HEAD")
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	entries, err := inspectProvenance(ctx, commits)
+	if err != nil {
+		t.Fatalf("inspectProvenance() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("inspectProvenance() returned %d entries, want 3", len(entries))
+	}
+
+	if e := entries[0]; !e.AIAssisted || !e.Note || e.Trailer || e.Record.Provider != "ollama" {
+		t.Errorf("noted commit = %+v", e)
+	}
+	if e := entries[1]; !e.AIAssisted || !e.Trailer || e.Note || e.Record.Model != "llama-3.3-70b-versatile" {
+		t.Errorf("trailer commit = %+v", e)
+	}
+	if e := entries[2]; e.AIAssisted || e.Record != nil {
+		t.Errorf("manual commit = %+v", e)
+	}
+}
@@ -0,0 +1,104 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+// errSecretsFound is returned when a commit is stopped because it contains secrets
+var errSecretsFound = errors.New("secrets found")
+
+// requestLabel names a provider request in what the user is told about it
+type requestLabel struct {
+	// diff names the diff, e.g. "the staged changes"
+	diff string
+	// source names the text sent along with the diff, e.g. "prompt"
+	source string
+	// action is what the user is asked whether to go on with when secrets
+	// are found, e.g. "Commit"
+	action string
+	// progress is shown while the provider works
+	progress string
+}
+
+var (
+	commitLabel    = requestLabel{diff: "the staged changes", source: "prompt", action: "Commit", progress: "Generating commit message"}
+	prLabel        = requestLabel{diff: "the branch", source: "prompt", action: "Write the pull request", progress: "Writing pull request description"}
+	changelogLabel = requestLabel{source: "changelog", action: "Summarize it", progress: "Summarizing releases"}
+)
+
+// redactDiff scans the diff for secrets and personal data and returns what may
+// be sent to the provider: local providers get the diff unchanged, all others
+// get placeholders. Secrets block the commit when configured, or when the user
+// declines to continue.
+func redactDiff(cmd *cobra.Command, cfg *config.Config, provider llm.Provider, providerName, diff string) (string, error) {
+	diff, _, err := redactRequest(cmd, cfg, provider, providerName, diff, "", commitLabel)
+	return diff, err
+}
+
+// redactRequest is redactDiff for a diff and the text sent along with it, such
+// as a prompt quoting commit messages, named as label says. Without a diff,
+// as for a changelog to summarize, only the text is scanned.
+func redactRequest(cmd *cobra.Command, cfg *config.Config, provider llm.Provider, providerName, diff, text string, label requestLabel) (string, string, error) {
+	if cfg.Redact.Disabled {
+		return diff, text, nil
+	}
+
+	scanner, err := redact.New(redact.Options{
+		Rules:      cfg.Redact.Rules,
+		Allow:      cfg.Redact.Allow,
+		AllowPaths: cfg.Redact.AllowPaths,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	redactedDiff, redactedText, report := scanner.RedactWith(diff, text, label.source)
+	if len(report.Findings) == 0 {
+		return diff, text, nil
+	}
+
+	found, sent := label.diff, "the diff"
+	if diff == "" {
+		found, sent = "the "+label.source, "the "+label.source
+	}
+	local := llm.IsLocal(provider)
+	out := cmd.ErrOrStderr()
+	if local {
+		fmt.Fprintf(out, "Found in %s (%s is local, nothing was redacted):\n", found, providerName)
+	} else {
+		fmt.Fprintf(out, "Redacted before sending %s to %s:\n", sent, providerName)
+	}
+	for _, f := range report.Findings {
+		fmt.Fprintf(out, "  - %s\n", f)
+	}
+
+	// Text alone is never committed, so only what leaves the machine matters
+	if report.HasSecrets() && (diff != "" || !local) {
+		if cfg.Redact.Block {
+			return "", "", fmt.Errorf("%w in %s (redact.block is set); remove them or add them to redact.allow", errSecretsFound, found)
+		}
+		if interactive(cmd) {
+			ok, err := askYesNo(cmd.InOrStdin(), out, fmt.Sprintf("Secrets were found in %s. %s anyway?", found, label.action))
+			if err != nil {
+				return "", "", err
+			}
+			if !ok {
+				return "", "", fmt.Errorf("%w in %s", errSecretsFound, found)
+			}
+		}
+	}
+
+	if local {
+		return diff, text, nil
+	}
+	return redactedDiff, redactedText, nil
+}
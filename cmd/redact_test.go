@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/spf13/cobra"
+)
+
+type stubProvider struct{ local bool }
+
+func (p stubProvider) GenerateCommitMessage(context.Context, string, string) (string, error) {
+	return "", nil
+}
+
+func (p stubProvider) Local() bool { return p.local }
+
+const envDiff = "diff --git a/.env b/.env\n--- /dev/null\n+++ b/.env\n@@ -0,0 +1 @@\n+OPENAI_API_KEY=sk-proj-abcdefghijklmnopqrstuvwx\n"
+
+func TestRedactDiff(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+
+	got, err := redactDiff(cmd, &config.Config{}, stubProvider{}, "openai", envDiff)
+	if err != nil {
+		t.Fatalf("redactDiff() error = %v", err)
+	}
+	if strings.Contains(got, "sk-proj-") || !strings.Contains(got, "[REDACTED:openai-key:1]") {
+		t.Errorf("redactDiff() = %q", got)
+	}
+	if !strings.Contains(stderr.String(), ".env: openai-key (secret)") {
+		t.Errorf("report = %q", stderr.String())
+	}
+
+	got, err = redactDiff(cmd, &config.Config{}, stubProvider{local: true}, "ollama", envDiff)
+	if err != nil || got != envDiff {
+		t.Errorf("redactDiff() for a local provider = %q, %v", got, err)
+	}
+
+	blocking := &config.Config{Redact: config.RedactConfig{Block: true}}
+	if _, err := redactDiff(cmd, blocking, stubProvider{}, "openai", envDiff); !errors.Is(err, errSecretsFound) {
+		t.Errorf("redactDiff() with block = %v, want errSecretsFound", err)
+	}
+
+	disabled := &config.Config{Redact: config.RedactConfig{Disabled: true, Block: true}}
+	if got, err := redactDiff(cmd, disabled, stubProvider{}, "openai", envDiff); err != nil || got != envDiff {
+		t.Errorf("redactDiff() when disabled = %q, %v", got, err)
+	}
+}
+
+func TestNewMessageRequestRecordsSentDiff(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetErr(&bytes.Buffer{})
+	a := &analysis{diff: envDiff}
+
+	req, err := newMessageRequest(cmd, &config.Config{DefaultProvider: "openai"}, a, "")
+	if err != nil {
+		t.Fatalf("newMessageRequest() error = %v", err)
+	}
+	// Provenance hashes what the provider received, not the raw staged diff
+	if a.sent != req.diff || a.sent == envDiff {
+		t.Errorf("sent = %q, want the redacted diff %q", a.sent, req.diff)
+	}
+}
+
+func TestNewMessageRequestRedactsPrompt(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+	a := &analysis{diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"}
+	commits := []git.Commit{{Message: "fix: rotate sk-proj-abcdefghijklmnopqrstuvwx\n"}}
+
+	req, err := newMessageRequest(cmd, &config.Config{DefaultProvider: "openai"}, a, squashPrompt(commits, ""))
+	if err != nil {
+		t.Fatalf("newMessageRequest() error = %v", err)
+	}
+	if strings.Contains(req.prompt, "sk-proj-") || !strings.Contains(req.prompt, "fix: rotate [REDACTED:openai-key:1]") {
+		t.Errorf("prompt = %q", req.prompt)
+	}
+	if !strings.Contains(stderr.String(), "prompt: openai-key (secret)") {
+		t.Errorf("report = %q", stderr.String())
+	}
+}
+
+func TestRedactRequestTextOnly(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetErr(&bytes.Buffer{})
+	const markdown = "### Fixed\n- rotate sk-proj-abcdefghijklmnopqrstuvwx\n"
+
+	_, got, err := redactRequest(cmd, &config.Config{}, stubProvider{}, "openai", "", markdown, changelogLabel)
+	if err != nil || strings.Contains(got, "sk-proj-") {
+		t.Errorf("redactRequest() = %q, %v", got, err)
+	}
+
+	blocking := &config.Config{Redact: config.RedactConfig{Block: true}}
+	if _, _, err := redactRequest(cmd, blocking, stubProvider{}, "openai", "", markdown, changelogLabel); !errors.Is(err, errSecretsFound) {
+		t.Errorf("redactRequest() with block = %v, want errSecretsFound", err)
+	}
+	// Nothing leaves the machine and a changelog is not committed
+	if _, got, err := redactRequest(cmd, blocking, stubProvider{local: true}, "ollama", "", markdown, changelogLabel); err != nil || got != markdown {
+		t.Errorf("redactRequest() for a local provider = %q, %v", got, err)
+	}
+}
+
+func TestRedactRequestLabel(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+
+	blocking := &config.Config{Redact: config.RedactConfig{Block: true}}
+	_, _, err := redactRequest(cmd, blocking, stubProvider{local: true}, "ollama", envDiff, "", prLabel)
+	if !errors.Is(err, errSecretsFound) || !strings.Contains(err.Error(), "secrets found in the branch") {
+		t.Errorf("redactRequest() with block = %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Found in the branch (ollama is local") {
+		t.Errorf("report = %q", stderr.String())
+	}
+}
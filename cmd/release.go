@@ -0,0 +1,192 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/changelog"
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+const (
+	preFlagName = "pre"
+	tagFlagName = "tag"
+
+	defaultTagPrefix = "v"
+)
+
+// versionTag is a tag naming a Semantic Version
+type versionTag struct {
+	name    string
+	version semver.Version
+}
+
+// versionTags returns the tags of the form <prefix><version> reachable from
+// merged, or on any branch when merged is empty, highest version first
+func versionTags(ctx context.Context, prefix, merged string) ([]versionTag, error) {
+	tags, err := git.Tags(ctx, merged, "")
+	if err != nil {
+		return nil, err
+	}
+	var versions []versionTag
+	for _, t := range tags {
+		rest, ok := strings.CutPrefix(t.Name, prefix)
+		if !ok || strings.HasPrefix(rest, "v") {
+			continue
+		}
+		if v, err := semver.Parse(rest); err == nil {
+			versions = append(versions, versionTag{name: t.Name, version: v})
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return semver.Compare(versions[i].version, versions[j].version) > 0 })
+	return versions, nil
+}
+
+// releasePlan is the next version and the changes that call for it
+type releasePlan struct {
+	// previous is the latest release tag, empty before the first release
+	previous string
+	bump     semver.Bump
+	next     semver.Version
+	tag      string
+	release  changelog.Release
+}
+
+// planRelease computes the version after the latest release tag reachable from
+// HEAD from the Conventional Commits since. Pre-releases are not a base: with
+// pre set the next version is numbered after the existing pre-releases of it.
+func planRelease(ctx context.Context, cfg config.ReleaseConfig, pre string) (releasePlan, error) {
+	prefix := cfg.TagPrefix
+	if prefix == "" {
+		prefix = defaultTagPrefix
+	}
+	if pre != "" {
+		if _, err := semver.Parse("0.0.0-" + pre); err != nil || strings.Contains(pre, ".") {
+			return releasePlan{}, fmt.Errorf("invalid pre-release identifier %q: use letters, digits and hyphens, e.g. rc", pre)
+		}
+	}
+	tags, err := versionTags(ctx, prefix, "HEAD")
+	if err != nil {
+		return releasePlan{}, err
+	}
+	var p releasePlan
+	var current semver.Version
+	for _, t := range tags {
+		if !t.version.IsPrerelease() {
+			p.previous, current = t.name, t.version
+			break
+		}
+	}
+
+	p.release, err = releaseRange{from: p.previous, to: "HEAD"}.release(ctx)
+	if err != nil {
+		return releasePlan{}, err
+	}
+	p.bump = p.release.Bump()
+	if p.bump == semver.None {
+		since := "the first commit"
+		if p.previous != "" {
+			since = p.previous
+		}
+		return releasePlan{}, fmt.Errorf("nothing to release: no features, fixes or breaking changes since %s", since)
+	}
+
+	p.next = semver.Rules{MajorOnZero: cfg.MajorOnZero}.Next(current, p.bump)
+	if pre != "" {
+		all, err := versionTags(ctx, prefix, "")
+		if err != nil {
+			return releasePlan{}, err
+		}
+		existing := make([]semver.Version, len(all))
+		for i, t := range all {
+			existing[i] = t.version
+		}
+		p.next = semver.Prerelease(p.next, pre, existing)
+	}
+	p.tag = prefix + p.next.String()
+	p.release.Version = p.tag
+	return p, nil
+}
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Compute and tag Semantic Versions from Conventional Commits",
+}
+
+var releaseNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Print the next version from the commits since the latest release tag",
+	Long: `Read the Conventional Commits since the latest release tag reachable from HEAD and print the version they call for: a major bump for breaking changes (a ! or a BREAKING CHANGE footer), minor for features and patch for fixes and other changes listed in the changelog. Commits such as docs and chore alone release nothing.
+
+Before 1.0.0 breaking changes bump the minor version; set release.major_on_zero to release 1.0.0 instead. --pre rc computes a pre-release such as v1.3.0-rc.2, numbered after the existing ones. Release tags are v<version> unless release.tag_prefix says otherwise.
+
+With --tag an annotated tag is created at HEAD whose message is a release summary written by the provider followed by the changelog. It is not pushed.
+
+  vibecheck release next
+  vibecheck release next --pre rc
+  vibecheck release next --tag && git push origin --tags`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		pre, err := cmd.Flags().GetString(preFlagName)
+		if err != nil {
+			return fmt.Errorf("get string pre flag: %w", err)
+		}
+		tag, err := cmd.Flags().GetBool(tagFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool tag flag: %w", err)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+
+		p, err := planRelease(ctx, cfg.Release, pre)
+		if err != nil {
+			return err
+		}
+		previous := p.previous
+		if previous == "" {
+			previous = "no release yet"
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s bump from %s\n", p.bump, previous)
+
+		if tag {
+			releases := []changelog.Release{p.release}
+			if err := summarize(cmd, cfg.Config, releases, additionalPrompt); err != nil {
+				return err
+			}
+			message := p.tag + "\n\n" + changelog.Notes(releases[0])
+			if err := git.CreateTag(ctx, p.tag, message); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Tagged %s; push it with git push origin %s\n", p.tag, p.tag)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), p.tag)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+	releaseCmd.AddCommand(releaseNextCmd)
+	releaseNextCmd.Flags().String(preFlagName, "", "used to compute a pre-release with this identifier, e.g. rc for v1.3.0-rc.1")
+	releaseNextCmd.Flags().Bool(tagFlagName, false, "used to create an annotated tag for the version with a release summary as its message")
+	releaseNextCmd.Flags().String(promptFlagName, "", "used to provide additional context for the release summary")
+	releaseNextCmd.Flags().String(providerFlagName, "", "used to select the ai-provider that writes the release summary")
+	releaseNextCmd.Flags().String(modelFlagName, "", "used to override the model of the selected provider")
+	releaseNextCmd.Flags().String(languageFlagName, "", "used to select the language the release summary is written in")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/semver"
+)
+
+func TestPlanRelease(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	ctx := context.Background()
+
+	run("commit", "-q", "--allow-empty", "-m", "chore: init")
+	if _, err := planRelease(ctx, config.ReleaseConfig{}, ""); err == nil {
+		t.Error("planRelease(chore only) error = nil")
+	}
+
+	run("commit", "-q", "--allow-empty", "-m", "feat!: first api")
+	p, err := planRelease(ctx, config.ReleaseConfig{}, "")
+	if err != nil || p.tag != "v0.1.0" || p.previous != "" || p.bump != semver.Major {
+		t.Errorf("planRelease(first) = %+v, %v", p, err)
+	}
+	if p, err := planRelease(ctx, config.ReleaseConfig{MajorOnZero: true, TagPrefix: "release-"}, ""); err != nil || p.tag != "release-1.0.0" {
+		t.Errorf("planRelease(major on zero) = %q, %v", p.tag, err)
+	}
+
+	run("tag", "v1.2.0")
+	run("tag", "not-a-version")
+	run("commit", "-q", "--allow-empty", "-m", "fix: two")
+	run("tag", "v1.2.1-rc.1")
+	if p, err := planRelease(ctx, config.ReleaseConfig{}, ""); err != nil || p.tag != "v1.2.1" || p.previous != "v1.2.0" {
+		t.Errorf("planRelease(fix) = %+v, %v", p, err)
+	}
+
+	run("commit", "-q", "--allow-empty", "-m", "feat(cli): three")
+	p, err = planRelease(ctx, config.ReleaseConfig{}, "")
+	if err != nil || p.tag != "v1.3.0" || p.bump != semver.Minor {
+		t.Errorf("planRelease(feat) = %+v, %v", p, err)
+	}
+	if len(p.release.Sections) != 2 || p.release.Version != "v1.3.0" {
+		t.Errorf("planRelease(feat) release = %+v", p.release)
+	}
+
+	// Pre-releases are numbered after existing ones on any branch
+	run("tag", "v1.3.0-rc.1")
+	run("checkout", "-q", "-b", "other", "v1.2.0")
+	run("commit", "-q", "--allow-empty", "-m", "feat: elsewhere")
+	run("tag", "v1.3.0-rc.2")
+	run("checkout", "-q", "-")
+	if p, err := planRelease(ctx, config.ReleaseConfig{}, "rc"); err != nil || p.tag != "v1.3.0-rc.3" {
+		t.Errorf("planRelease(rc) = %q, %v", p.tag, err)
+	}
+	if p, err := planRelease(ctx, config.ReleaseConfig{}, "beta"); err != nil || p.tag != "v1.3.0-beta.1" {
+		t.Errorf("planRelease(beta) = %q, %v", p.tag, err)
+	}
+	if _, err := planRelease(ctx, config.ReleaseConfig{}, "rc.1"); err == nil {
+		t.Error("planRelease(rc.1) error = nil")
+	}
+}
@@ -0,0 +1,451 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+	"github.com/rshdhere/vibecheck/internal/stats"
+	"github.com/rshdhere/vibecheck/internal/ui/notify"
+	"github.com/spf13/cobra"
+)
+
+const (
+	jobsFlagName = "jobs"
+
+	// backupRefPrefix is where the history before a reword or squash is kept
+	backupRefPrefix = "refs/vibecheck/backup/"
+)
+
+// saveBackup keeps rev under a ref of its own for command, named after the
+// current branch and the time, so that a later reword or squash never
+// replaces the undo point of an earlier one
+func saveBackup(ctx context.Context, command, rev string) (string, error) {
+	branch, err := git.CurrentBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if branch == "" {
+		branch = "HEAD"
+	}
+	name := fmt.Sprintf("%s%s/%s/%s", backupRefPrefix, command, branch, time.Now().Format("20060102-150405"))
+	ref := name
+	for i := 2; ; i++ {
+		if _, err := git.RevParse(ctx, ref); err != nil {
+			break
+		}
+		ref = fmt.Sprintf("%s-%d", name, i)
+	}
+	if err := git.UpdateRef(ctx, ref, rev); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+// rewordPlan is the history reword rewrites
+type rewordPlan struct {
+	// base is the commit the series is rebased onto, empty for the root
+	base string
+	// series is every commit between base and HEAD, oldest first
+	series []git.Commit
+	// selected holds the hashes of the commits that get a new message
+	selected map[string]bool
+}
+
+// planReword resolves a single commit or a base..HEAD range into the history
+// to rewrite. Only commits leading to HEAD can be reworded.
+func planReword(ctx context.Context, arg string) (rewordPlan, error) {
+	var plan rewordPlan
+	head, err := git.RevParse(ctx, "HEAD")
+	if err != nil {
+		return plan, err
+	}
+
+	if strings.Contains(arg, "...") {
+		return plan, fmt.Errorf("reword takes a commit or a <base>..HEAD range, not %q", arg)
+	}
+	if from, to, ok := strings.Cut(arg, ".."); ok {
+		if to != "" {
+			if target, err := git.RevParse(ctx, to); err != nil || target != head {
+				return plan, fmt.Errorf("%q does not end at HEAD; check out the branch to reword first", arg)
+			}
+		}
+		if from == "" {
+			from = "HEAD"
+		}
+		if _, err := git.RevParse(ctx, from); err != nil {
+			return plan, err
+		}
+		// Rebasing onto the tip of main would also move the branch onto it
+		if plan.base, err = git.MergeBase(ctx, from, head); err != nil {
+			return plan, err
+		}
+	} else {
+		commit, err := git.RevParse(ctx, arg)
+		if err != nil {
+			return plan, err
+		}
+		if base, err := git.MergeBase(ctx, commit, head); err != nil || base != commit {
+			return plan, fmt.Errorf("%s is not an ancestor of HEAD", arg)
+		}
+		if plan.base, err = git.Parent(ctx, commit); err != nil {
+			return plan, err
+		}
+		if plan.base == git.EmptyTree {
+			plan.base = ""
+		}
+		plan.selected = map[string]bool{commit: true}
+	}
+
+	revs := []string{"HEAD"}
+	if plan.base != "" {
+		revs = []string{plan.base + "..HEAD"}
+	}
+	merges, err := git.Log(ctx, append([]string{"--merges"}, revs...)...)
+	if err != nil {
+		return plan, err
+	}
+	if len(merges) > 0 {
+		return plan, fmt.Errorf("cannot reword across merge commits such as %s", shortHash(merges[0].Hash))
+	}
+	if plan.series, err = git.Log(ctx, append([]string{"--reverse"}, revs...)...); err != nil {
+		return plan, err
+	}
+	if len(plan.series) == 0 {
+		return plan, fmt.Errorf("no commits in %s", arg)
+	}
+	if plan.selected == nil {
+		plan.selected = make(map[string]bool, len(plan.series))
+		for _, c := range plan.series {
+			plan.selected[c.Hash] = true
+		}
+	}
+	return plan, nil
+}
+
+// rewording is one commit getting a new message
+type rewording struct {
+	commit git.Commit
+	a      *analysis
+	// req is nil when vibecheck describes the commit locally
+	req     *messageRequest
+	message string
+	latency float64
+}
+
+// generateAll runs the provider calls of rewordings, at most jobs at a time
+func generateAll(ctx context.Context, rewordings []*rewording, jobs int) error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		first error
+	)
+	sem := make(chan struct{}, jobs)
+	for _, r := range rewordings {
+		if r.req == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(r *rewording) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			message, latency, err := r.req.run(ctx)
+			if err == nil && strings.TrimSpace(message) == "" {
+				err = errEmptyMessage
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if first == nil {
+					first = err
+				}
+				return
+			}
+			r.message, r.latency = message, latency
+		}(r)
+	}
+	wg.Wait()
+	return first
+}
+
+// noteRewordings moves the provenance notes of the series to the rewritten
+// commits, which the rebase leaves behind, and with notes set also records
+// each regenerated message on its new commit
+func noteRewordings(ctx context.Context, cfg *config.Resolved, plan rewordPlan, rewordings []*rewording, messages map[string]string) error {
+	rev := "HEAD"
+	if plan.base != "" {
+		rev = plan.base + "..HEAD"
+	}
+	rewritten, err := git.Log(ctx, "--reverse", rev)
+	if err != nil {
+		return err
+	}
+	if len(rewritten) != len(plan.series) {
+		return fmt.Errorf("the rewritten history has %s, not %d", commitCount(len(rewritten)), len(plan.series))
+	}
+	noted, err := git.NotedCommits(ctx, provenance.NotesRef)
+	if err != nil {
+		return err
+	}
+	regenerated := make(map[string]*rewording)
+	for _, r := range rewordings {
+		if r.req != nil && messages[r.commit.Hash] != "" {
+			regenerated[r.commit.Hash] = r
+		}
+	}
+
+	for i, c := range plan.series {
+		to := rewritten[i].Hash
+		if r, ok := regenerated[c.Hash]; ok && cfg.Provenance.Notes {
+			record := provenance.Record{
+				Tool:          provenance.Tool,
+				Version:       version,
+				Provider:      cfg.DefaultProvider,
+				Model:         providerModel(cfg.DefaultProvider, cfg.Model),
+				Latency:       r.latency,
+				PromptVersion: llm.PromptVersion,
+				DiffHash:      provenance.HashDiff(r.a.sent),
+			}
+			if err := addProvenanceNote(ctx, to, record); err != nil {
+				return err
+			}
+			continue
+		}
+		if noted[c.Hash] && to != c.Hash {
+			if err := git.CopyNote(ctx, provenance.NotesRef, c.Hash, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeRewording shows the old and the new message of a commit
+func writeRewording(w io.Writer, r *rewording) {
+	fmt.Fprintf(w, "%s\n", shortHash(r.commit.Hash))
+	for _, line := range strings.Split(r.commit.Message, "\n") {
+		fmt.Fprintf(w, "- %s\n", line)
+	}
+	for _, line := range strings.Split(r.message, "\n") {
+		fmt.Fprintf(w, "+ %s\n", line)
+	}
+	fmt.Fprintln(w)
+}
+
+// commitCount returns "1 commit" or "n commits"
+func commitCount(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+var rewordCmd = &cobra.Command{
+	Use:   "reword <commit> | <base>..HEAD",
+	Short: "Regenerate the messages of existing commits",
+	Long: `Regenerate the message of one commit, or of every commit in a range such as main..HEAD, from its diff. The old and new messages are shown for approval before the history is rewritten with a rebase; trailers such as Signed-off-by are kept.
+
+The branch as it was is saved under refs/vibecheck/backup/reword/<branch>/<time>, a new ref for every reword, and the command prints how to undo it:
+
+  git reset --hard refs/vibecheck/backup/reword/<branch>/<time>
+
+git for-each-ref refs/vibecheck/backup lists every saved branch.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		jobs, err := cmd.Flags().GetInt(jobsFlagName)
+		if err != nil {
+			return fmt.Errorf("get int jobs flag: %w", err)
+		}
+		if jobs < 1 {
+			return fmt.Errorf("--%s must be at least 1", jobsFlagName)
+		}
+		dry, err := cmd.Flags().GetBool(dryRunFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool dry-run flag: %w", err)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+		issueKey, err := cmd.Flags().GetString(issueFlagName)
+		if err != nil {
+			return fmt.Errorf("get string issue flag: %w", err)
+		}
+		if !interactive(cmd) {
+			notify.SetPlain(true)
+		}
+
+		plan, err := planReword(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		// The rebase needs a clean tree; find out before paying for the provider
+		if clean, err := git.Clean(ctx); err != nil {
+			return err
+		} else if !clean && !dry {
+			return errors.New("commit or stash your changes before rewording history")
+		}
+
+		opts, err := diffOptions(cfg.Diff)
+		if err != nil {
+			return err
+		}
+		patchOpts := opts
+		patchOpts.StatOnly = false
+
+		var rewordings []*rewording
+		for _, c := range plan.series {
+			if !plan.selected[c.Hash] {
+				continue
+			}
+			diff, err := git.RevisionDiff(ctx, patchOpts, c.Hash)
+			if err != nil {
+				return err
+			}
+			src, err := revisionSource(ctx, c.Hash)
+			if err != nil {
+				return err
+			}
+			a, err := analyzeDiff(ctx, cfg, diff, src, issueKey)
+			if err != nil {
+				return err
+			}
+			if err := a.requireIssue(cfg); err != nil {
+				return err
+			}
+			if opts.StatOnly {
+				a.statOnly = true
+				if a.diff, err = git.PatchStat(ctx, diff); err != nil {
+					return err
+				}
+			}
+			r := &rewording{commit: c, a: a, message: a.localMessage}
+			if r.message == "" {
+				if r.req, err = newMessageRequest(cmd, cfg.Config, a, additionalPrompt); err != nil {
+					return err
+				}
+			}
+			rewordings = append(rewordings, r)
+		}
+
+		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithColor("cyan"), spinner.WithWriterFile(os.Stderr))
+		s.Suffix = fmt.Sprintf(" Generating %d commit messages...", len(rewordings))
+		s.Start()
+		err = generateAll(ctx, rewordings, jobs)
+		s.Stop()
+		if err != nil {
+			return providerError(cmd, cfg.DefaultProvider, err)
+		}
+
+		out := cmd.OutOrStdout()
+		messages := make(map[string]string)
+		for _, r := range rewordings {
+			if r.message, err = r.a.finalize(r.message); err != nil {
+				return err
+			}
+			isBreaking, err := confirmBreaking(cmd, r.a.breaking)
+			if err != nil {
+				return fmt.Errorf("confirm breaking change: %w", err)
+			}
+			if isBreaking {
+				r.message = markBreaking(r.message, r.a.breaking)
+			}
+			// Sign-offs and other trailers of the original commit stay
+			trailers, err := git.ParseMessageTrailers(ctx, r.commit.Message)
+			if err != nil {
+				return err
+			}
+			if r.message, err = git.InterpretTrailers(ctx, r.message, trailers); err != nil {
+				return err
+			}
+			if strings.TrimSpace(r.message) == strings.TrimSpace(r.commit.Message) {
+				continue
+			}
+			writeRewording(out, r)
+			messages[r.commit.Hash] = r.message
+		}
+		if len(messages) == 0 {
+			fmt.Fprintln(out, "Every message is already up to date")
+			return nil
+		}
+		if dry {
+			return nil
+		}
+
+		yes, err := cmd.Flags().GetBool(yesFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool yes flag: %w", err)
+		}
+		if !yes {
+			if !isTerminal(os.Stdin) {
+				return fmt.Errorf("pass --%s to rewrite history without asking", yesFlagName)
+			}
+			ok, err := askYesNo(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Rewrite the messages of %s?", commitCount(len(messages))))
+			if err != nil || !ok {
+				return err
+			}
+		}
+
+		backup, err := saveBackup(ctx, "reword", "HEAD")
+		if err != nil {
+			return err
+		}
+		hashes := make([]string, len(plan.series))
+		for i, c := range plan.series {
+			hashes[i] = c.Hash
+		}
+		if err := git.Reword(ctx, plan.base, hashes, messages); err != nil {
+			return fmt.Errorf("%w; the previous history is saved as %s", err, backup)
+		}
+
+		fmt.Fprintf(out, "Reworded %s; undo with git reset --hard %s\n", commitCount(len(messages)), backup)
+		// The history is rewritten already, so a failed note is only worth a warning
+		if err := noteRewordings(ctx, cfg, plan, rewordings, messages); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: provenance notes: %v\n", err)
+		}
+		for _, r := range rewordings {
+			if r.req != nil && messages[r.commit.Hash] != "" {
+				subject, _, _ := strings.Cut(r.message, "\n")
+				_ = stats.RecordCommit(cfg.DefaultProvider, r.latency, subject)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rewordCmd)
+	addMessageFlags(rewordCmd)
+	addDiffFlags(rewordCmd)
+	rewordCmd.Flags().Int(jobsFlagName, 4, "used to limit how many messages are generated at the same time")
+	rewordCmd.Flags().Bool(dryRunFlagName, false, "used to show the new messages without rewriting history")
+	rewordCmd.Flags().BoolP(yesFlagName, "y", false, "used to rewrite history without asking")
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+)
+
+func TestPlanReword(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	for _, msg := range []string{"init", "wip", "more wip"} {
+		run("commit", "-q", "--allow-empty", "-m", msg)
+	}
+
+	ctx := context.Background()
+	root, _ := git.RevParse(ctx, "HEAD~2")
+	middle, _ := git.RevParse(ctx, "HEAD~1")
+
+	plan, err := planReword(ctx, "HEAD~2..HEAD")
+	if err != nil {
+		t.Fatalf("planReword(range) error = %v", err)
+	}
+	if plan.base != root || len(plan.series) != 2 || len(plan.selected) != 2 || plan.series[0].Hash != middle {
+		t.Errorf("planReword(range) = %+v", plan)
+	}
+
+	plan, err = planReword(ctx, "HEAD~1")
+	if err != nil {
+		t.Fatalf("planReword(commit) error = %v", err)
+	}
+	if plan.base != root || len(plan.series) != 2 || !plan.selected[middle] || len(plan.selected) != 1 {
+		t.Errorf("planReword(commit) = %+v", plan)
+	}
+
+	// The root commit is rewritten with git rebase --root
+	plan, err = planReword(ctx, root)
+	if err != nil || plan.base != "" || len(plan.series) != 3 {
+		t.Errorf("planReword(root) = %+v, %v", plan, err)
+	}
+
+	// A base that moved on is rebased onto where the branch forked, not its tip
+	run("checkout", "-q", "-b", "upstream", "HEAD~1")
+	run("commit", "-q", "--allow-empty", "-m", "upstream moved on")
+	run("checkout", "-q", "-")
+	plan, err = planReword(ctx, "upstream..HEAD")
+	if err != nil || plan.base != middle || len(plan.series) != 1 {
+		t.Errorf("planReword(diverged range) = %+v, %v", plan, err)
+	}
+
+	for _, arg := range []string{"HEAD~2..HEAD~1", "HEAD~2...HEAD", "no-such-rev"} {
+		if _, err := planReword(ctx, arg); err == nil {
+			t.Errorf("planReword(%q) error = nil", arg)
+		}
+	}
+}
+
+func TestWriteRewording(t *testing.T) {
+	var b bytes.Buffer
+	writeRewording(&b, &rewording{
+		commit:  git.Commit{Hash: "0123456789abcdef", Message: "wip"},
+		message: "feat: add reword\n\nRegenerates messages.",
+	})
+	want := "0123456\n- wip\n+ feat: add reword\n+ \n+ Regenerates messages.\n\n"
+	if b.String() != want {
+		t.Errorf("writeRewording() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestGenerateAllEmptyMessage(t *testing.T) {
+	rewordings := []*rewording{
+		{message: "docs: fix typo"},
+		{req: &messageRequest{provider: stubProvider{}, providerName: "openai"}},
+	}
+	if err := generateAll(context.Background(), rewordings, 2); !errors.Is(err, errEmptyMessage) {
+		t.Errorf("generateAll() error = %v, want errEmptyMessage", err)
+	}
+}
+
+func TestSaveBackup(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	run("checkout", "-q", "-b", "topic")
+	run("commit", "-q", "--allow-empty", "-m", "init")
+
+	ctx := context.Background()
+	reword, err := saveBackup(ctx, "reword", "HEAD")
+	if err != nil || !strings.HasPrefix(reword, "refs/vibecheck/backup/reword/topic/") {
+		t.Fatalf("saveBackup(reword) = %q, %v", reword, err)
+	}
+	squash, err := saveBackup(ctx, "squash", "HEAD")
+	if err != nil || !strings.HasPrefix(squash, "refs/vibecheck/backup/squash/topic/") {
+		t.Fatalf("saveBackup(squash) = %q, %v", squash, err)
+	}
+	// A second reword gets a ref of its own, even within the same second
+	again, err := saveBackup(ctx, "reword", "HEAD")
+	if err != nil || again == reword {
+		t.Errorf("saveBackup(reword) again = %q, %v; first was %q", again, err, reword)
+	}
+	head, _ := git.RevParse(ctx, "HEAD")
+	for _, ref := range []string{reword, squash, again} {
+		if got, err := git.RevParse(ctx, ref); err != nil || got != head {
+			t.Errorf("%s = %q, %v, want %s", ref, got, err, head)
+		}
+	}
+}
+
+func TestNoteRewordings(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+	run("commit", "-q", "--allow-empty", "-m", "init")
+	for _, name := range []string{"a", "b", "c"} {
+		run("commit", "-q", "--allow-empty", "-m", "wip "+name)
+		run("notes", "--ref", provenance.NotesRef, "add", "-m", "note "+name)
+	}
+
+	ctx := context.Background()
+	plan, err := planReword(ctx, "HEAD~3..HEAD")
+	if err != nil {
+		t.Fatalf("planReword() error = %v", err)
+	}
+	a, b, c := plan.series[0], plan.series[1], plan.series[2]
+	rewordings := []*rewording{
+		{commit: b, a: &analysis{sent: "diff b"}, req: &messageRequest{}, message: "feat: add b"},
+		{commit: c, a: &analysis{}, message: "docs: add c"},
+	}
+	messages := map[string]string{b.Hash: "feat: add b\n", c.Hash: "docs: add c\n"}
+	if err := git.Reword(ctx, plan.base, []string{a.Hash, b.Hash, c.Hash}, messages); err != nil {
+		t.Fatalf("Reword() error = %v", err)
+	}
+
+	cfg := &config.Resolved{Config: &config.Config{DefaultProvider: "ollama", Provenance: config.ProvenanceConfig{Notes: true}}}
+	if err := noteRewordings(ctx, cfg, plan, rewordings, messages); err != nil {
+		t.Fatalf("noteRewordings() error = %v", err)
+	}
+
+	want := map[string]string{
+		// Untouched, and regenerated, and described locally
+		"HEAD~2": "note a",
+		"HEAD~1": `"provider": "ollama"`,
+		"HEAD":   "note c",
+	}
+	for rev, text := range want {
+		note, ok, err := git.ReadNote(ctx, provenance.NotesRef, rev)
+		if err != nil || !ok || !strings.Contains(note, text) {
+			t.Errorf("note on %s = %q, %v, %v, want %q", rev, note, ok, err, text)
+		}
+	}
+}
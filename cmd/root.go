@@ -26,7 +26,7 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -36,6 +36,7 @@ func init() {
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.vibecheck.yaml)")
+	rootCmd.PersistentFlags().String(profileFlagName, "", "select a named configuration profile (or set VIBECHECK_PROFILE)")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
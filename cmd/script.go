@@ -0,0 +1,139 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	yesFlagName    = "yes"
+	dryRunFlagName = "dry-run"
+	printFlagName  = "print"
+	outputFlagName = "output"
+
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Exit codes of vibecheck, so scripts and CI can tell failures apart
+const (
+	exitFailure       = 1
+	exitNothingStaged = 2
+	exitMissingSetup  = 3
+	exitSecrets       = 4
+)
+
+// exitError carries the exit code for err. A reported error has already been
+// shown to the user and is not printed again.
+type exitError struct {
+	code     int
+	err      error
+	reported bool
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// reportedError returns an error that exits with code without being printed
+// again, since cmd has already told the user what went wrong
+func reportedError(cmd *cobra.Command, code int, err error) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &exitError{code: code, err: err, reported: true}
+}
+
+// exitCode maps err to the exit code of the process
+func exitCode(err error) int {
+	var e *exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &e):
+		return e.code
+	case errors.Is(err, errSecretsFound):
+		return exitSecrets
+	default:
+		return exitFailure
+	}
+}
+
+// interactive reports whether cmd may ask the user questions: stdin must be a
+// terminal and --yes must not be set
+func interactive(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup(yesFlagName); f != nil && f.Value.String() == "true" {
+		return false
+	}
+	return isTerminal(os.Stdin)
+}
+
+// addScriptFlags registers the flags for running vibecheck from scripts and CI
+func addScriptFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(dryRunFlagName, false, "used to print the message that would be committed without committing")
+	cmd.Flags().Bool(printFlagName, false, "same as --dry-run")
+	cmd.Flags().String(outputFlagName, outputText, "used to select the output format: text or json (message, provider, model, latency and tokens)")
+}
+
+// dryRun reports whether --dry-run or --print was given
+func dryRun(cmd *cobra.Command) (bool, error) {
+	for _, name := range []string{dryRunFlagName, printFlagName} {
+		on, err := cmd.Flags().GetBool(name)
+		if err != nil {
+			return false, fmt.Errorf("get bool %s flag: %w", name, err)
+		}
+		if on {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// outputFormat reads and validates --output
+func outputFormat(cmd *cobra.Command) (string, error) {
+	format, err := cmd.Flags().GetString(outputFlagName)
+	if err != nil {
+		return "", fmt.Errorf("get string output flag: %w", err)
+	}
+	switch format {
+	case outputText, outputJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown --%s %q: use %s or %s", outputFlagName, format, outputText, outputJSON)
+	}
+}
+
+// result is what vibecheck commit reports with --output json
+type result struct {
+	Message  string `json:"message"`
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+	// Latency is the provider call in seconds
+	Latency float64 `json:"latency"`
+	llm.Usage
+	// Local is set when vibecheck wrote the message without a provider
+	Local     bool   `json:"local"`
+	Committed bool   `json:"committed"`
+	Commit    string `json:"commit,omitempty"`
+}
+
+// writeResult prints r in format; text is the message alone
+func writeResult(w io.Writer, format string, r result) error {
+	if format != outputJSON {
+		_, err := fmt.Fprintln(w, strings.TrimSpace(r.Message))
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+func TestExitCode(t *testing.T) {
+	cmd := &cobra.Command{}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, 0},
+		{"failure", errors.New("boom"), exitFailure},
+		{"nothing staged", reportedError(cmd, exitNothingStaged, errors.New("nothing is staged")), exitNothingStaged},
+		{"wrapped", fmt.Errorf("generate: %w", reportedError(cmd, exitMissingSetup, errors.New("key"))), exitMissingSetup},
+		{"secrets", fmt.Errorf("%w (redact.block is set)", errSecretsFound), exitSecrets},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if !cmd.SilenceErrors || !cmd.SilenceUsage {
+		t.Error("reportedError() did not silence the command")
+	}
+}
+
+func TestDryRunAndOutput(t *testing.T) {
+	cmd := &cobra.Command{}
+	addScriptFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--print", "--output", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if dry, err := dryRun(cmd); err != nil || !dry {
+		t.Errorf("dryRun() = %v, %v", dry, err)
+	}
+	if format, err := outputFormat(cmd); err != nil || format != outputJSON {
+		t.Errorf("outputFormat() = %q, %v", format, err)
+	}
+
+	if err := cmd.Flags().Set(outputFlagName, "yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := outputFormat(cmd); err == nil {
+		t.Error("outputFormat(yaml) error = nil")
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	r := result{
+		Message:  "feat: add json output\n",
+		Provider: "openai",
+		Model:    "gpt-4o-mini",
+		Latency:  1.5,
+		Usage:    llm.Usage{InputTokens: 120, OutputTokens: 12},
+	}
+
+	var text bytes.Buffer
+	if err := writeResult(&text, outputText, r); err != nil || text.String() != "feat: add json output\n" {
+		t.Errorf("writeResult(text) = %q, %v", text.String(), err)
+	}
+
+	var out bytes.Buffer
+	if err := writeResult(&out, outputJSON, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"provider": "openai"`, `"input_tokens": 120`, `"output_tokens": 12`, `"latency": 1.5`, `"committed": false`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeResult(json) = %s, missing %s", out.String(), want)
+		}
+	}
+}
+
+func TestInteractiveWithYes(t *testing.T) {
+	cmd := &cobra.Command{}
+	addCommitFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--yes"}); err != nil {
+		t.Fatal(err)
+	}
+	if interactive(cmd) {
+		t.Error("interactive() = true with --yes")
+	}
+}
@@ -0,0 +1,290 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rshdhere/vibecheck/internal/breaking"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/ignore"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/patch"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+	"github.com/rshdhere/vibecheck/internal/split"
+	"github.com/rshdhere/vibecheck/internal/stats"
+	"github.com/rshdhere/vibecheck/internal/ui/notify"
+	"github.com/spf13/cobra"
+)
+
+// writeSplit lists the proposed commits and their hunks
+func writeSplit(w io.Writer, groups []split.Group, hunks []patch.Hunk) {
+	byID := make(map[int]patch.Hunk, len(hunks))
+	for _, h := range hunks {
+		byID[h.ID] = h
+	}
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%d. %s\n", i+1, strings.ReplaceAll(strings.TrimSpace(g.Message), "\n", "\n   "))
+		for _, id := range g.Hunks {
+			h := byID[id]
+			fmt.Fprintf(w, "   - %s (+%d -%d)\n", h.Title(), h.Added, h.Deleted)
+		}
+	}
+}
+
+// commitSplit commits groups in order, staging only the hunks of each with
+// git apply --cached. If a step fails, HEAD and the index are put back exactly
+// as they were.
+func commitSplit(ctx context.Context, groups []split.Group, hunks []patch.Hunk) error {
+	tree, err := git.WriteTree(ctx)
+	if err != nil {
+		return err
+	}
+	head, err := git.RevParse(ctx, "HEAD")
+	if err != nil {
+		return err
+	}
+	restore := func(err error) error {
+		resetErr := git.ResetSoft(ctx, head)
+		if resetErr == nil {
+			resetErr = git.ReadTree(ctx, tree)
+		}
+		if resetErr != nil {
+			return fmt.Errorf("%w; restoring the index failed: %v; restore it with git reset --soft %s && git read-tree %s", err, resetErr, head, tree)
+		}
+		return fmt.Errorf("%w; the staged changes were restored", err)
+	}
+
+	byID := make(map[int]patch.Hunk, len(hunks))
+	for _, h := range hunks {
+		byID[h.ID] = h
+	}
+	if err := git.ReadTree(ctx, "HEAD"); err != nil {
+		return restore(err)
+	}
+	for i, g := range groups {
+		selected := make([]patch.Hunk, len(g.Hunks))
+		for j, id := range g.Hunks {
+			selected[j] = byID[id]
+		}
+		if err := git.ApplyCached(ctx, patch.Join(selected)); err != nil {
+			return restore(fmt.Errorf("stage commit %d: %w", i+1, err))
+		}
+		if err := git.CommitWMessage(ctx, g.Message, git.CommitOptions{NoEdit: true}); err != nil {
+			return restore(fmt.Errorf("commit %d: %w", i+1, err))
+		}
+	}
+	// The commits must add up to what was staged, hunk offsets and all
+	if final, err := git.WriteTree(ctx); err != nil {
+		return restore(err)
+	} else if final != tree {
+		return restore(errors.New("the commits do not add up to the staged changes"))
+	}
+	return nil
+}
+
+// groupFindings returns the breaking change findings in the files the hunks
+// of g touch
+func groupFindings(findings []breaking.Finding, g split.Group, hunks []patch.Hunk) []breaking.Finding {
+	paths := make(map[string]bool)
+	for _, h := range hunks {
+		if slices.Contains(g.Hunks, h.ID) {
+			paths[h.Path] = true
+		}
+	}
+	var found []breaking.Finding
+	for _, f := range findings {
+		if paths[f.File] {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Split the staged changes into several atomic commits",
+	Long: `Ask the provider to group the staged hunks into logically separate changes, such as a bug fix, a refactoring and a docs tweak, each with its own Conventional Commit message. The proposal opens in a review where hunks can be moved between commits and messages edited; the commits are then made in order, each staging only its hunks with git apply --cached.
+
+New, deleted, renamed and binary files move as a whole. If staging or committing fails, for example in a pre-commit hook, the commits made so far are undone and the index is restored exactly as it was.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		dry, err := cmd.Flags().GetBool(dryRunFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool dry-run flag: %w", err)
+		}
+		yes, err := cmd.Flags().GetBool(yesFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool yes flag: %w", err)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+		if !interactive(cmd) {
+			notify.SetPlain(true)
+		}
+		if !yes && !dry && !isTerminal(os.Stdin) {
+			return fmt.Errorf("pass --%s to commit the proposed split without review", yesFlagName)
+		}
+
+		if _, err := git.RevParse(ctx, "HEAD"); err != nil {
+			return errors.New("split needs a commit to build on; make the first commit with vibecheck commit")
+		}
+		staged, err := git.StagedPatch(ctx)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(staged) == "" {
+			notify.ShowStageReminder()
+			return reportedError(cmd, exitNothingStaged, errors.New("nothing is staged"))
+		}
+		hunks := patch.Hunks(patch.Parse(staged))
+		if len(hunks) < 2 {
+			return errors.New("only one hunk is staged, which cannot be split; commit it with vibecheck commit")
+		}
+
+		diff, err := git.StagedDiff(ctx)
+		if err != nil {
+			return err
+		}
+		a, err := analyzeStaged(ctx, cfg, diff, nil, "")
+		if err != nil {
+			return err
+		}
+		ignored, err := ignore.Load(cfg.RepoRoot, cfg.Ignore)
+		if err != nil {
+			return err
+		}
+		provider, err := llm.GetProvider(cfg.DefaultProvider)
+		if err != nil {
+			return err
+		}
+		listing, err := redactDiff(cmd, cfg.Config, provider, cfg.DefaultProvider, split.Describe(hunks, ignored.Match))
+		if err != nil {
+			return err
+		}
+		req := &messageRequest{
+			provider:     provider,
+			providerName: cfg.DefaultProvider,
+			model:        cfg.Model,
+			diff:         listing,
+			prompt:       buildPromptContext(cfg.Config, a, additionalPrompt),
+		}
+
+		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithColor("cyan"), spinner.WithWriterFile(os.Stderr))
+		s.Suffix = fmt.Sprintf(" Grouping %d hunks...", len(hunks))
+		s.Start()
+		response, latency, err := req.run(llm.WithSystemPrompt(ctx, split.SystemPrompt))
+		s.Stop()
+		if err != nil {
+			return providerError(cmd, cfg.DefaultProvider, err)
+		}
+		groups, err := split.Parse(response, len(hunks))
+		if err != nil {
+			return err
+		}
+		for i := range groups {
+			if groups[i].Message, err = a.finalize(groups[i].Message); err != nil {
+				return err
+			}
+		}
+
+		if dry {
+			writeSplit(cmd.OutOrStdout(), groups, hunks)
+			return nil
+		}
+		if !yes {
+			final, err := tea.NewProgram(newSplitModel(groups, hunks)).Run()
+			if err != nil {
+				return fmt.Errorf("error running program: %w", err)
+			}
+			review := final.(splitModel)
+			if !review.confirmed {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Split cancelled; nothing was committed")
+				return nil
+			}
+			groups = review.result()
+		}
+
+		for i := range groups {
+			findings := groupFindings(a.breaking, groups[i], hunks)
+			if len(findings) == 0 {
+				continue
+			}
+			subject, _, _ := strings.Cut(groups[i].Message, "\n")
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", subject)
+			isBreaking, err := confirmBreaking(cmd, findings)
+			if err != nil {
+				return fmt.Errorf("confirm breaking change: %w", err)
+			}
+			if isBreaking {
+				groups[i].Message = markBreaking(groups[i].Message, findings)
+			}
+		}
+
+		var topts trailerOptions
+		if cfg.Provenance.Trailer {
+			record := provenance.Record{
+				Tool:          provenance.Tool,
+				Version:       version,
+				Provider:      cfg.DefaultProvider,
+				Model:         providerModel(cfg.DefaultProvider, cfg.Model),
+				Latency:       latency,
+				PromptVersion: llm.PromptVersion,
+				DiffHash:      provenance.HashDiff(req.diff),
+			}
+			topts.generatedBy = record.TrailerValue()
+		}
+		trailers, err := collectTrailers(ctx, cfg.Config, a, topts)
+		if err != nil {
+			return err
+		}
+		for i := range groups {
+			if groups[i].Message, err = git.InterpretTrailers(ctx, groups[i].Message, trailers); err != nil {
+				return err
+			}
+		}
+
+		if err := commitSplit(ctx, groups, hunks); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Split the staged changes into %s\n", commitCount(len(groups)))
+		for _, g := range groups {
+			subject, _, _ := strings.Cut(g.Message, "\n")
+			_ = stats.RecordCommit(cfg.DefaultProvider, latency, subject)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	splitCmd.Flags().String(promptFlagName, "", "used to provide additional context to llm")
+	splitCmd.Flags().String(providerFlagName, "", "used to select the ai-provider that groups the hunks")
+	splitCmd.Flags().String(modelFlagName, "", "used to override the model of the selected provider")
+	splitCmd.Flags().String(styleFlagName, "", "used to describe the commit message style, e.g. \"conventional, no bullets\"")
+	splitCmd.Flags().String(languageFlagName, "", "used to select the language the commit messages are written in")
+	splitCmd.Flags().Bool(dryRunFlagName, false, "used to print the proposed commits without committing")
+	splitCmd.Flags().BoolP(yesFlagName, "y", false, "used to commit the proposed split without the review")
+	splitCmd.Flags().Bool(breakingFlagName, false, "used to mark every commit with a possible breaking change without asking (--breaking=false never marks one)")
+}
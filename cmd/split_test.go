@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rshdhere/vibecheck/internal/breaking"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/patch"
+	"github.com/rshdhere/vibecheck/internal/split"
+)
+
+func TestCommitSplit(t *testing.T) {
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	defer os.Chdir(wd)
+	os.Chdir(repo)
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v error = %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "-q")
+	run("config", "user.name", "Jane Doe")
+	run("config", "user.email", "jane@example.com")
+
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, strings.Repeat("x", i))
+	}
+	original := strings.Join(lines, "\n") + "\n"
+	os.WriteFile("a.txt", []byte(original), 0644)
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	// Two hunks far apart in a.txt and a new file
+	lines[0], lines[19] = "first", "last"
+	os.WriteFile("a.txt", []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	os.WriteFile("b.txt", []byte("new\n"), 0644)
+	run("add", ".")
+	// Unstaged changes stay where they are
+	os.WriteFile("c.txt", []byte("untracked\n"), 0644)
+
+	ctx := context.Background()
+	staged, err := git.StagedPatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hunks := patch.Hunks(patch.Parse(staged))
+	if len(hunks) != 3 {
+		t.Fatalf("got %d hunks, want 3", len(hunks))
+	}
+	tree := run("write-tree")
+	head := run("rev-parse", "HEAD")
+
+	// A failing hook on the second commit restores everything
+	hook := filepath.Join(".git", "hooks", "pre-commit")
+	os.WriteFile(hook, []byte("#!/bin/sh\ntest ! -f .git/second || exit 1\ntouch .git/second\n"), 0755)
+	groups := []split.Group{
+		{Message: "fix: change the last line", Hunks: []int{2}},
+		{Message: "feat: add b and the first line", Hunks: []int{1, 3}},
+	}
+	if err := commitSplit(ctx, groups, hunks); err == nil || !strings.Contains(err.Error(), "restored") {
+		t.Errorf("commitSplit(failing hook) error = %v", err)
+	}
+	if got := run("rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD = %s after a failed split, want %s", got, head)
+	}
+	if got := run("write-tree"); got != tree {
+		t.Error("the index was not restored after a failed split")
+	}
+	os.Remove(hook)
+
+	if err := commitSplit(ctx, groups, hunks); err != nil {
+		t.Fatalf("commitSplit() error = %v", err)
+	}
+	if got := run("log", "--format=%s", "-3"); got != "feat: add b and the first line\nfix: change the last line\ninit" {
+		t.Errorf("log = %q", got)
+	}
+	if got := run("show", "--format=", "--name-only", "HEAD~1"); got != "a.txt" {
+		t.Errorf("first commit touched %q", got)
+	}
+	if got := run("rev-parse", "HEAD^{tree}"); got != tree {
+		t.Error("the commits do not add up to the staged tree")
+	}
+	if _, err := os.Stat("c.txt"); err != nil {
+		t.Error("the working tree was changed")
+	}
+}
+
+func TestSplitModel(t *testing.T) {
+	hunks := []patch.Hunk{
+		{ID: 1, Path: "a.go", Text: "@@ -1 +1 @@\n-a\n+b\n"},
+		{ID: 2, Path: "a.go", Text: "@@ -9 +9 @@\n-c\n+d\n"},
+		{ID: 3, Path: "README.md"},
+	}
+	m := newSplitModel([]split.Group{
+		{Message: "fix: one\n\nBody.", Hunks: []int{1, 2}},
+		{Message: "docs: two", Hunks: []int{3}},
+	}, hunks)
+
+	press := func(keys ...string) {
+		for _, k := range keys {
+			var msg tea.KeyMsg
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "right":
+				msg = tea.KeyMsg{Type: tea.KeyRight}
+			case "down":
+				msg = tea.KeyMsg{Type: tea.KeyDown}
+			case "ctrl+u":
+				msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+			default:
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			}
+			next, _ := m.Update(msg)
+			m = next.(splitModel)
+		}
+	}
+
+	// Move the second hunk to the docs commit; it stays selected
+	press("down", "right")
+	want := []split.Group{{Message: "fix: one\n\nBody.", Hunks: []int{1}}, {Message: "docs: two", Hunks: []int{2, 3}}}
+	if !reflect.DeepEqual(m.groups, want) || m.rows()[m.cursor].hunk != 2 {
+		t.Errorf("after move groups = %+v, cursor %d", m.groups, m.cursor)
+	}
+
+	// Move the first hunk to a new commit, leaving the fix empty, and name it
+	press("k", "n", "ctrl+u", "refactor: three", "enter")
+	got := m.result()
+	want = []split.Group{{Message: "docs: two", Hunks: []int{2, 3}}, {Message: "refactor: three", Hunks: []int{1}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("result() = %+v, want %+v", got, want)
+	}
+
+	// Editing keeps the body
+	m.groups[1].Message = "docs: two\n\nBody."
+	press("k", "k", "e", "ctrl+u", "docs: four", "enter", "enter")
+	if got := m.result()[0].Message; got != "docs: four\n\nBody." || !m.confirmed {
+		t.Errorf("after edit message = %q, confirmed %v", got, m.confirmed)
+	}
+}
+
+func TestGroupFindings(t *testing.T) {
+	hunks := []patch.Hunk{
+		{ID: 1, Path: "api.go"},
+		{ID: 2, Path: "cli.go"},
+		{ID: 3, Path: "README.md"},
+	}
+	findings := []breaking.Finding{
+		{Kind: breaking.KindRemoved, File: "api.go", Name: "Run"},
+		{Kind: breaking.KindSignature, File: "cli.go", Name: "Execute"},
+	}
+
+	got := groupFindings(findings, split.Group{Message: "refactor: drop Run", Hunks: []int{1, 3}}, hunks)
+	if !reflect.DeepEqual(got, findings[:1]) {
+		t.Errorf("groupFindings() = %v, want %v", got, findings[:1])
+	}
+	if got := groupFindings(findings, split.Group{Message: "docs: update", Hunks: []int{3}}, hunks); len(got) != 0 {
+		t.Errorf("groupFindings() for docs = %v, want none", got)
+	}
+}
@@ -0,0 +1,295 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rshdhere/vibecheck/internal/patch"
+	"github.com/rshdhere/vibecheck/internal/split"
+)
+
+// previewLines limits the diff shown for the selected hunk
+const previewLines = 12
+
+// splitRow is a hunk as listed in the review, under its group
+type splitRow struct {
+	group int
+	hunk  int
+}
+
+// splitModel reviews the proposed commits of vibecheck split: hunks can be
+// moved between groups and the subject of each message edited
+type splitModel struct {
+	groups    []split.Group
+	hunks     map[int]patch.Hunk
+	cursor    int
+	textInput textinput.Model
+	state     string // "list", "input"
+	confirmed bool
+	quitting  bool
+	errorMsg  string
+}
+
+func newSplitModel(groups []split.Group, hunks []patch.Hunk) splitModel {
+	byID := make(map[int]patch.Hunk, len(hunks))
+	for _, h := range hunks {
+		byID[h.ID] = h
+	}
+	ti := textinput.New()
+	ti.Placeholder = "type(scope): summary"
+	ti.CharLimit = 200
+	ti.Width = 72
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	return splitModel{groups: groups, hunks: byID, textInput: ti, state: "list"}
+}
+
+// rows lists the hunks in review order
+func (m splitModel) rows() []splitRow {
+	var rows []splitRow
+	for g, group := range m.groups {
+		for _, id := range group.Hunks {
+			rows = append(rows, splitRow{group: g, hunk: id})
+		}
+	}
+	return rows
+}
+
+// result returns the groups to commit, leaving out those without hunks
+func (m splitModel) result() []split.Group {
+	var groups []split.Group
+	for _, g := range m.groups {
+		if len(g.Hunks) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// move puts the selected hunk in group to and keeps it selected
+func (m *splitModel) move(to int) {
+	rows := m.rows()
+	if len(rows) == 0 || to < 0 {
+		return
+	}
+	row := rows[m.cursor]
+	if to == row.group {
+		return
+	}
+	if to == len(m.groups) {
+		m.groups = append(m.groups, split.Group{})
+	}
+	from := &m.groups[row.group]
+	for i, id := range from.Hunks {
+		if id == row.hunk {
+			from.Hunks = append(from.Hunks[:i:i], from.Hunks[i+1:]...)
+			break
+		}
+	}
+	m.groups[to].Hunks = append(m.groups[to].Hunks, row.hunk)
+	sort.Ints(m.groups[to].Hunks)
+	for i, r := range m.rows() {
+		if r.hunk == row.hunk {
+			m.cursor = i
+		}
+	}
+}
+
+// edit starts editing the subject of the selected hunk's group
+func (m *splitModel) edit() tea.Cmd {
+	rows := m.rows()
+	if len(rows) == 0 {
+		return nil
+	}
+	subject, _, _ := strings.Cut(m.groups[rows[m.cursor].group].Message, "\n")
+	m.state = "input"
+	m.errorMsg = ""
+	m.textInput.SetValue(subject)
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+	return textinput.Blink
+}
+
+func (m splitModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m splitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	rows := m.rows()
+
+	if m.state == "input" {
+		switch keyMsg.String() {
+		case "ctrl+c", "esc":
+			m.state = "list"
+			m.textInput.Blur()
+			return m, nil
+		case "enter":
+			subject := strings.TrimSpace(m.textInput.Value())
+			if subject == "" {
+				m.errorMsg = "The subject cannot be empty"
+				return m, nil
+			}
+			group := &m.groups[rows[m.cursor].group]
+			if _, body, ok := strings.Cut(group.Message, "\n"); ok {
+				subject += "\n" + body
+			}
+			group.Message = subject
+			m.state = "list"
+			m.errorMsg = ""
+			m.textInput.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "q", "esc":
+		m.quitting = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(rows)-1 {
+			m.cursor++
+		}
+	case "left", "h":
+		m.move(rows[m.cursor].group - 1)
+	case "right", "l":
+		if to := rows[m.cursor].group + 1; to < len(m.groups) {
+			m.move(to)
+		}
+	case "n":
+		m.move(len(m.groups))
+		return m, m.edit()
+	case "e":
+		return m, m.edit()
+	case "enter":
+		for _, g := range m.result() {
+			if strings.TrimSpace(g.Message) == "" {
+				m.errorMsg = "Every commit needs a message; select one of its hunks and press e"
+				return m, nil
+			}
+		}
+		m.confirmed = true
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m splitModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	// Define color palette
+	var (
+		primaryColor   = lipgloss.Color("205")
+		secondaryColor = lipgloss.Color("140")
+		mutedColor     = lipgloss.Color("240")
+		borderColor    = lipgloss.Color("238")
+		addedColor     = lipgloss.Color("42")
+		deletedColor   = lipgloss.Color("203")
+		errorColor     = lipgloss.Color("196")
+	)
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor).MarginTop(1).MarginBottom(1)
+	messageStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	selectedStyle := lipgloss.NewStyle().Foreground(primaryColor).Bold(true)
+	addedStyle := lipgloss.NewStyle().Foreground(addedColor)
+	deletedStyle := lipgloss.NewStyle().Foreground(deletedColor)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("VIBECHECK SPLIT") + "\n")
+
+	rows := m.rows()
+	selected := splitRow{group: -1}
+	if len(rows) > 0 {
+		selected = rows[m.cursor]
+	}
+	for g, group := range m.groups {
+		subject, _, _ := strings.Cut(group.Message, "\n")
+		if subject == "" {
+			subject = mutedStyle.Render("(no message)")
+		}
+		if m.state == "input" && g == selected.group {
+			subject = m.textInput.View()
+		}
+		b.WriteString(fmt.Sprintf("%s %s\n", mutedStyle.Render(fmt.Sprintf("%d.", g+1)), messageStyle.Render(subject)))
+		if len(group.Hunks) == 0 {
+			b.WriteString(mutedStyle.Render("     no hunks, left out") + "\n")
+		}
+		for _, id := range group.Hunks {
+			h := m.hunks[id]
+			counts := addedStyle.Render(fmt.Sprintf("+%d", h.Added)) + " " + deletedStyle.Render(fmt.Sprintf("-%d", h.Deleted))
+			if id == selected.hunk {
+				b.WriteString(fmt.Sprintf("   %s %s\n", selectedStyle.Render("> "+h.Title()), counts))
+			} else {
+				b.WriteString(fmt.Sprintf("     %s %s\n", h.Title(), counts))
+			}
+		}
+	}
+
+	if h, ok := m.hunks[selected.hunk]; ok && h.Text != "" {
+		lines := strings.Split(strings.TrimRight(h.Text, "\n"), "\n")[1:]
+		if len(lines) > previewLines {
+			lines = append(lines[:previewLines], "…")
+		}
+		for i, line := range lines {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				lines[i] = addedStyle.Render(line)
+			case strings.HasPrefix(line, "-"):
+				lines[i] = deletedStyle.Render(line)
+			default:
+				lines[i] = mutedStyle.Render(line)
+			}
+		}
+		previewStyle := lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(borderColor).
+			Padding(0, 1).
+			MarginTop(1)
+		b.WriteString(previewStyle.Render(strings.Join(lines, "\n")) + "\n")
+	}
+
+	if m.errorMsg != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(errorColor).MarginTop(1).Render(m.errorMsg) + "\n")
+	}
+
+	// Help bar at the bottom
+	helpStyle := lipgloss.NewStyle().
+		Foreground(mutedColor).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderTop(true).
+		BorderForeground(borderColor).
+		MarginTop(1)
+	helpKeyStyle := lipgloss.NewStyle().Foreground(secondaryColor).Bold(true)
+	help := []string{"↑/↓", "select", "←/→", "move to commit", "n", "new commit", "e", "edit message", "enter", "commit all", "q", "quit"}
+	if m.state == "input" {
+		help = []string{"enter", "save", "esc", "cancel"}
+	}
+	var parts []string
+	for i := 0; i < len(help); i += 2 {
+		parts = append(parts, helpKeyStyle.Render(help[i])+" "+mutedStyle.Render(help[i+1]))
+	}
+	b.WriteString(helpStyle.Render(strings.Join(parts, "  ")))
+	return b.String()
+}
@@ -0,0 +1,238 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/llm"
+	"github.com/rshdhere/vibecheck/internal/prompt"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+	"github.com/rshdhere/vibecheck/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+const commitFlagName = "commit"
+
+// commitList lists the messages of commits for a prompt, one bullet each
+func commitList(commits []git.Commit) string {
+	var list strings.Builder
+	for _, c := range commits {
+		subject, body, _ := strings.Cut(c.Message, "\n")
+		fmt.Fprintf(&list, "- %s\n", subject)
+		for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				fmt.Fprintf(&list, "  %s\n", line)
+			}
+		}
+	}
+	return list.String()
+}
+
+// squashPrompt asks for one message covering commits, given oldest first
+func squashPrompt(commits []git.Commit, userPrompt string) string {
+	var b prompt.Builder
+	b.Add("Commits being squashed, oldest first", commitList(commits))
+	b.Add("Squash", "Write one Conventional Commit message for the whole branch: a header summarising it and a body of bullet points, one per notable change. Describe the combined diff, not the history of the commits.")
+	b.Add("", userPrompt)
+	return b.String()
+}
+
+// squashTrailers returns the distinct trailers of commits, such as the
+// sign-offs and co-authors of everyone who worked on the branch
+func squashTrailers(ctx context.Context, commits []git.Commit) ([]git.Trailer, error) {
+	var trailers []git.Trailer
+	seen := make(map[git.Trailer]bool)
+	for _, c := range commits {
+		parsed, err := git.ParseMessageTrailers(ctx, c.Message)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range parsed {
+			if !seen[t] {
+				seen[t] = true
+				trailers = append(trailers, t)
+			}
+		}
+	}
+	return trailers, nil
+}
+
+var squashCmd = &cobra.Command{
+	Use:   "squash <base>",
+	Short: "Write one message for squash-merging the current branch",
+	Long: `Gather the commit messages and the combined diff of the current branch since it left <base> and write a single Conventional Commit with a bullet body, ready for a squash merge. Trailers of the squashed commits, such as Signed-off-by and Co-authored-by, are kept.
+
+With --commit the branch is squashed as well: it is reset with git reset --soft to where it left <base> and committed with the message. The branch as it was is saved under refs/vibecheck/backup/squash/<branch>/<time>, a new ref for every squash, and the command prints how to undo it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := resolveConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("resolve config: %w", err)
+		}
+		commit, err := cmd.Flags().GetBool(commitFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool commit flag: %w", err)
+		}
+		noEdit, err := cmd.Flags().GetBool(noEditFlagName)
+		if err != nil {
+			return fmt.Errorf("get bool no-edit flag: %w", err)
+		}
+		additionalPrompt, err := cmd.Flags().GetString(promptFlagName)
+		if err != nil {
+			return fmt.Errorf("get string prompt flag: %w", err)
+		}
+		issueKey, err := cmd.Flags().GetString(issueFlagName)
+		if err != nil {
+			return fmt.Errorf("get string issue flag: %w", err)
+		}
+
+		base, err := git.MergeBase(ctx, args[0], "HEAD")
+		if err != nil {
+			return err
+		}
+		commits, err := git.Log(ctx, "--reverse", base+"..HEAD")
+		if err != nil {
+			return err
+		}
+		if len(commits) == 0 {
+			return fmt.Errorf("the branch has no commits since %s", args[0])
+		}
+		// reset --soft would take staged changes into the squashed commit
+		if commit {
+			if clean, err := git.Clean(ctx); err != nil {
+				return err
+			} else if !clean {
+				return errors.New("commit or stash your changes before squashing")
+			}
+		}
+
+		opts, err := diffOptions(cfg.Diff)
+		if err != nil {
+			return err
+		}
+		patchOpts := opts
+		patchOpts.StatOnly = false
+		rev := base + "..HEAD"
+		diff, err := git.RevisionDiff(ctx, patchOpts, rev)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(diff) == "" {
+			return fmt.Errorf("%w: the branch has no changes since %s", errNoDiff, args[0])
+		}
+		src, err := revisionSource(ctx, rev)
+		if err != nil {
+			return err
+		}
+		a, err := analyzeDiff(ctx, cfg, diff, src, issueKey)
+		if err != nil {
+			return err
+		}
+		if err := a.requireIssue(cfg); err != nil {
+			return err
+		}
+		if opts.StatOnly {
+			a.statOnly = true
+			if a.diff, err = git.PatchStat(ctx, diff); err != nil {
+				return err
+			}
+		}
+
+		generated := a.localMessage == ""
+		message, latency := a.localMessage, 0.0
+		if generated {
+			message, latency, err = generateMessage(cmd, cfg.Config, a, squashPrompt(commits, additionalPrompt))
+			if err != nil {
+				return err
+			}
+		}
+		if message, err = a.finalize(message); err != nil {
+			return err
+		}
+		isBreaking, err := confirmBreaking(cmd, a.breaking)
+		if err != nil {
+			return fmt.Errorf("confirm breaking change: %w", err)
+		}
+		if isBreaking {
+			message = markBreaking(message, a.breaking)
+		}
+
+		var topts trailerOptions
+		record := provenance.Record{
+			Tool:          provenance.Tool,
+			Version:       version,
+			Provider:      cfg.DefaultProvider,
+			Model:         providerModel(cfg.DefaultProvider, cfg.Model),
+			Latency:       latency,
+			PromptVersion: llm.PromptVersion,
+			DiffHash:      provenance.HashDiff(a.sent),
+		}
+		if generated && cfg.Provenance.Trailer {
+			topts.generatedBy = record.TrailerValue()
+		}
+		trailers, err := squashTrailers(ctx, commits)
+		if err != nil {
+			return err
+		}
+		extra, err := collectTrailers(ctx, cfg.Config, a, topts)
+		if err != nil {
+			return err
+		}
+		if message, err = git.InterpretTrailers(ctx, message, append(trailers, extra...)); err != nil {
+			return err
+		}
+
+		if !commit {
+			fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSpace(message))
+			return nil
+		}
+
+		head, err := git.RevParse(ctx, "HEAD")
+		if err != nil {
+			return err
+		}
+		backup, err := saveBackup(ctx, "squash", head)
+		if err != nil {
+			return err
+		}
+		if err := git.ResetSoft(ctx, base); err != nil {
+			return err
+		}
+		if err := git.CommitWMessage(ctx, message, git.CommitOptions{NoEdit: noEdit}); err != nil {
+			// Put the branch back rather than leave the whole branch staged
+			if resetErr := git.ResetSoft(ctx, head); resetErr != nil {
+				return fmt.Errorf("commit with message: %w; the branch is saved as %s", err, backup)
+			}
+			return fmt.Errorf("commit with message: %w", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Squashed %s; undo with git reset --hard %s\n", commitCount(len(commits)), backup)
+
+		if generated && cfg.Provenance.Notes {
+			// The squash is done, so a failed note is only worth a warning
+			if err := addProvenanceNote(ctx, "HEAD", record); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: provenance note: %v\n", err)
+			}
+		}
+		if generated {
+			subject, _, _ := strings.Cut(message, "\n")
+			_ = stats.RecordCommit(cfg.DefaultProvider, latency, subject)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(squashCmd)
+	addMessageFlags(squashCmd)
+	addDiffFlags(squashCmd)
+	squashCmd.Flags().Bool(commitFlagName, false, "used to squash the branch into one commit with the message, instead of only printing it")
+	squashCmd.Flags().Bool(noEditFlagName, false, "used to commit the message without opening the editor")
+}
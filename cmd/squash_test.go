@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+)
+
+func TestSquashPrompt(t *testing.T) {
+	commits := []git.Commit{
+		{Message: "wip"},
+		{Message: "feat: add squash\n\nGathers the branch.\n\nSigned-off-by: Jane <jane@example.com>"},
+	}
+	got := squashPrompt(commits, "mention the issue")
+	want := "Commits being squashed, oldest first:\n- wip\n- feat: add squash\n  Gathers the branch.\n  Signed-off-by: Jane <jane@example.com>"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("squashPrompt() = %q, want prefix %q", got, want)
+	}
+	if !strings.Contains(got, "bullet points") || !strings.HasSuffix(got, "\n\nmention the issue") {
+		t.Errorf("squashPrompt() = %q", got)
+	}
+}
+
+func TestSquashTrailers(t *testing.T) {
+	commits := []git.Commit{
+		{Message: "wip\n\nSigned-off-by: Jane <jane@example.com>"},
+		{Message: "more\n\nSigned-off-by: Jane <jane@example.com>\nCo-authored-by: Sam <sam@example.com>"},
+		{Message: "no trailers"},
+	}
+	got, err := squashTrailers(context.Background(), commits)
+	if err != nil {
+		t.Fatalf("squashTrailers() error = %v", err)
+	}
+	want := []git.Trailer{
+		{Token: "Signed-off-by", Value: "Jane <jane@example.com>"},
+		{Token: "Co-authored-by", Value: "Sam <sam@example.com>"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("squashTrailers() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+// Package cmd is provided by cobra-cli to ship command-line tools faster
+/*
+Copyright © 2025 raashed
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/rshdhere/vibecheck/internal/issue"
+	"github.com/rshdhere/vibecheck/internal/provenance"
+)
+
+const (
+	signoffFlagName  = "signoff"
+	coauthorFlagName = "co-author"
+)
+
+// trailerOptions are the trailer choices made for a single commit
+type trailerOptions struct {
+	signoff   bool
+	coauthors []string
+	// generatedBy is the provenance trailer value, empty unless enabled
+	generatedBy string
+}
+
+// collectTrailers assembles the configured static trailers, the issue footer,
+// the provenance trailer, the selected co-authors and the sign-off, in that order
+func collectTrailers(ctx context.Context, cfg *config.Config, a *analysis, opts trailerOptions) ([]git.Trailer, error) {
+	var trailers []git.Trailer
+
+	for _, line := range cfg.Trailers {
+		t, ok := git.ParseTrailer(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid trailer %q, want \"Token: value\"", line)
+		}
+		trailers = append(trailers, t)
+	}
+
+	if token, value, ok := issue.Footer(a.issueKey, a.issuePlacement, a.issueFooter); ok {
+		trailers = append(trailers, git.Trailer{Token: token, Value: value})
+	}
+
+	if opts.generatedBy != "" {
+		trailers = append(trailers, git.Trailer{Token: provenance.TrailerToken, Value: opts.generatedBy})
+	}
+
+	for _, name := range opts.coauthors {
+		ident, err := resolveCoauthor(cfg.Coauthors, name)
+		if err != nil {
+			return nil, err
+		}
+		trailers = append(trailers, git.Trailer{Token: "Co-authored-by", Value: ident})
+	}
+
+	if opts.signoff || cfg.Signoff {
+		ident, err := git.CommitterIdent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		trailers = append(trailers, git.Trailer{Token: "Signed-off-by", Value: ident})
+	}
+
+	return trailers, nil
+}
+
+// resolveCoauthor expands a pair partner alias; full "Name <email>" values are used as-is
+func resolveCoauthor(coauthors map[string]string, name string) (string, error) {
+	if ident, ok := coauthors[name]; ok {
+		return ident, nil
+	}
+	if strings.Contains(name, "<") && strings.HasSuffix(name, ">") {
+		return name, nil
+	}
+
+	known := make([]string, 0, len(coauthors))
+	for alias := range coauthors {
+		known = append(known, alias)
+	}
+	sort.Strings(known)
+	return "", fmt.Errorf("unknown co-author %q (configured: %s)", name, strings.Join(known, ", "))
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/config"
+	"github.com/rshdhere/vibecheck/internal/git"
+)
+
+func TestCollectTrailers(t *testing.T) {
+	cfg := &config.Config{
+		Trailers:  []string{"Reviewed-by: Jane Doe <jane@example.com>"},
+		Coauthors: map[string]string{"john": "John Roe <john@example.com>"},
+	}
+	a := &analysis{issueKey: "PAY-1234", issuePlacement: "footer"}
+
+	got, err := collectTrailers(context.Background(), cfg, a, trailerOptions{
+		coauthors:   []string{"john", "Ann Lee <ann@example.com>"},
+		generatedBy: "vibecheck/dev (openai/gpt-4o-mini)",
+	})
+	if err != nil {
+		t.Fatalf("collectTrailers() error = %v", err)
+	}
+
+	want := []git.Trailer{
+		{Token: "Reviewed-by", Value: "Jane Doe <jane@example.com>"},
+		{Token: "Refs", Value: "PAY-1234"},
+		{Token: "Generated-by", Value: "vibecheck/dev (openai/gpt-4o-mini)"},
+		{Token: "Co-authored-by", Value: "John Roe <john@example.com>"},
+		{Token: "Co-authored-by", Value: "Ann Lee <ann@example.com>"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectTrailers() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCollectTrailersErrors(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := collectTrailers(ctx, &config.Config{Trailers: []string{"garbage"}}, &analysis{}, trailerOptions{}); err == nil {
+		t.Error("collectTrailers() with malformed static trailer should return error")
+	}
+	if _, err := collectTrailers(ctx, &config.Config{}, &analysis{}, trailerOptions{coauthors: []string{"nobody"}}); err == nil {
+		t.Error("collectTrailers() with unknown co-author should return error")
+	}
+}
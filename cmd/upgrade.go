@@ -14,6 +14,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"syscall"
@@ -22,6 +23,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
+	"github.com/rshdhere/vibecheck/internal/semver"
 	"github.com/rshdhere/vibecheck/internal/ui/banner"
 )
 
@@ -78,17 +80,9 @@ var upgradeCmd = &cobra.Command{
 			return fmt.Errorf("failed to fetch latest release: %w", err)
 		}
 
-		latestVersion := strings.TrimPrefix(release.TagName, "v")
-		currentVersion := strings.TrimPrefix(version, "v")
-
-		// Remove git describe suffix for comparison (e.g., -3-ge034ae7-dirty)
-		if idx := strings.Index(currentVersion, "-"); idx != -1 {
-			currentVersion = currentVersion[:idx]
-		}
-
 		s.Stop()
 
-		if currentVersion == latestVersion {
+		if upToDate(version, release.TagName) {
 			fmt.Printf("✅ Already on the latest version: %s\n", version)
 			fmt.Println()
 			banner.Print()
@@ -149,6 +143,26 @@ var upgradeCmd = &cobra.Command{
 	},
 }
 
+// describeSuffix is what git describe appends to builds made after a tag,
+// e.g. -3-ge034ae7-dirty
+var describeSuffix = regexp.MustCompile(`(-\d+-g[0-9a-f]+)?(-dirty)?$`)
+
+// upToDate reports whether the installed version is the latest release or
+// newer, comparing them as Semantic Versions so that pre-releases and builds
+// ahead of a release are handled. Builds without a version, such as dev,
+// are never up to date.
+func upToDate(installed, latest string) bool {
+	latestVersion, err := semver.Parse(latest)
+	if err != nil {
+		return strings.TrimPrefix(installed, "v") == strings.TrimPrefix(latest, "v")
+	}
+	installedVersion, err := semver.Parse(describeSuffix.ReplaceAllString(installed, ""))
+	if err != nil {
+		return false
+	}
+	return semver.Compare(installedVersion, latestVersion) >= 0
+}
+
 func fetchLatestRelease() (*GitHubRelease, error) {
 	resp, err := http.Get(githubAPIURL)
 	if err != nil {
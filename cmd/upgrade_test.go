@@ -152,3 +152,26 @@ func TestExtractBinary(t *testing.T) {
 		}
 	})
 }
+
+func TestUpToDate(t *testing.T) {
+	tests := []struct {
+		installed string
+		latest    string
+		want      bool
+	}{
+		{installed: "v1.2.0", latest: "v1.2.0", want: true},
+		{installed: "1.2.0", latest: "v1.2.0", want: true},
+		{installed: "v1.10.0", latest: "v1.9.0", want: true},
+		{installed: "v1.9.0", latest: "v1.10.0", want: false},
+		{installed: "v1.2.0-3-ge034ae7-dirty", latest: "v1.2.0", want: true},
+		{installed: "v1.2.0-dirty", latest: "v1.2.0", want: true},
+		{installed: "v1.3.0-rc.1", latest: "v1.2.0", want: true},
+		{installed: "v1.3.0-rc.1", latest: "v1.3.0", want: false},
+		{installed: "dev", latest: "v1.2.0", want: false},
+	}
+	for _, tt := range tests {
+		if got := upToDate(tt.installed, tt.latest); got != tt.want {
+			t.Errorf("upToDate(%q, %q) = %v, want %v", tt.installed, tt.latest, got, tt.want)
+		}
+	}
+}
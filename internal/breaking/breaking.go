@@ -0,0 +1,201 @@
+// Package breaking flags changes in a diff that are likely to break users:
+// removed or renamed exported Go identifiers, changed function signatures,
+// deleted command-line flags and removed configuration keys
+package breaking
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind classifies a finding
+type Kind string
+
+const (
+	KindRemoved   Kind = "removed"
+	KindSignature Kind = "signature"
+	KindFlag      Kind = "flag"
+	KindConfigKey Kind = "config"
+)
+
+// Finding is one likely breaking change
+type Finding struct {
+	Kind Kind
+	File string
+	Name string
+	// Old and New are the declaration lines before and after, New is empty for removals
+	Old string
+	New string
+}
+
+func (f Finding) String() string {
+	switch f.Kind {
+	case KindSignature:
+		return fmt.Sprintf("%s: signature of %s changed", f.File, f.Name)
+	case KindFlag:
+		return fmt.Sprintf("%s: flag %s removed", f.File, f.Name)
+	case KindConfigKey:
+		return fmt.Sprintf("%s: config key %s removed", f.File, f.Name)
+	default:
+		return fmt.Sprintf("%s: exported %s removed or renamed", f.File, f.Name)
+	}
+}
+
+var (
+	// funcDecl matches "func Name(" and "func (r *T) Name(", capturing the receiver type and name
+	funcDecl = regexp.MustCompile(`^func\s+(?:\(\s*\w*\s*\*?\s*(\w+)(?:\[[^\]]*\])?\s*\)\s*)?([A-Z]\w*)\s*[\[(]`)
+	// typeDecl matches top-level "type Name ..." declarations
+	typeDecl = regexp.MustCompile(`^type\s+([A-Z]\w*)\b`)
+	// valueDecl matches single-line top-level "var Name" and "const Name" declarations
+	valueDecl = regexp.MustCompile(`^(?:var|const)\s+([A-Z]\w*)\b`)
+	// flagDecl matches cobra/pflag registrations such as Flags().StringP("name", ...) or Flags().Bool(nameFlag, ...)
+	flagDecl = regexp.MustCompile(`Flags\(\)\.\w+\(\s*("[^"]+"|\w+)`)
+	// configTag matches json and yaml struct tag names
+	configTag = regexp.MustCompile(`(?:json|yaml):"([A-Za-z0-9_.-]+)`)
+)
+
+// declaration is a matched line together with its identity
+type declaration struct {
+	kind Kind
+	key  string
+	name string
+	file string
+	line string
+}
+
+// Detect scans a unified diff and reports likely breaking changes. Test files
+// are ignored, and declarations that were only moved are not reported.
+func Detect(diff string) []Finding {
+	removed := map[string]declaration{}
+	added := map[string]declaration{}
+	var order []string
+
+	file, inHunk := "", false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			file, inHunk = "", false
+			continue
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			continue
+		case !inHunk && strings.HasPrefix(line, "--- "):
+			file = diffPath(line[4:])
+			continue
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			if p := diffPath(line[4:]); p != "" {
+				file = p
+			}
+			continue
+		}
+		if !inHunk || !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") || line == "" {
+			continue
+		}
+
+		sign, body := line[0], line[1:]
+		if sign != '-' && sign != '+' {
+			continue
+		}
+		for _, d := range declarations(file, body) {
+			if sign == '-' {
+				if _, seen := removed[d.key]; !seen {
+					order = append(order, d.key)
+				}
+				removed[d.key] = d
+			} else {
+				added[d.key] = d
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, key := range order {
+		old := removed[key]
+		repl, ok := added[key]
+		switch {
+		case !ok:
+			findings = append(findings, Finding{Kind: old.kind, File: old.file, Name: old.name, Old: old.line})
+		case old.kind == KindRemoved && normalize(repl.line) != normalize(old.line) && strings.HasPrefix(old.line, "func"):
+			findings = append(findings, Finding{Kind: KindSignature, File: old.file, Name: old.name, Old: old.line, New: repl.line})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].File < findings[j].File })
+	return findings
+}
+
+// declarations returns the API-relevant declarations on a single diff line
+func declarations(file, line string) []declaration {
+	trimmed := strings.TrimSpace(line)
+	var out []declaration
+
+	// Top-level declarations are not indented
+	if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+		if m := funcDecl.FindStringSubmatch(trimmed); m != nil {
+			name := m[2]
+			if m[1] != "" {
+				name = m[1] + "." + m[2]
+			}
+			out = append(out, declaration{kind: KindRemoved, key: "func " + name, name: name, file: file, line: trimmed})
+		} else if m := typeDecl.FindStringSubmatch(trimmed); m != nil {
+			out = append(out, declaration{kind: KindRemoved, key: "type " + m[1], name: m[1], file: file, line: trimmed})
+		} else if m := valueDecl.FindStringSubmatch(trimmed); m != nil {
+			out = append(out, declaration{kind: KindRemoved, key: "value " + m[1], name: m[1], file: file, line: trimmed})
+		}
+	}
+
+	if m := flagDecl.FindStringSubmatch(trimmed); m != nil {
+		// Literal names are shown as --name, constants by their identifier
+		name := m[1]
+		if strings.HasPrefix(name, `"`) {
+			name = "--" + strings.Trim(name, `"`)
+		}
+		out = append(out, declaration{kind: KindFlag, key: "flag " + name, name: name, file: file, line: trimmed})
+	}
+	// Struct tags elsewhere usually describe wire formats rather than user settings
+	if strings.Contains(file, "config") {
+		for _, m := range configTag.FindAllStringSubmatch(trimmed, -1) {
+			out = append(out, declaration{kind: KindConfigKey, key: "config " + m[1], name: m[1], file: file, line: trimmed})
+		}
+	}
+	return out
+}
+
+// diffPath strips the a/ or b/ prefix from a diff header path; /dev/null yields ""
+func diffPath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(p, "a/") || strings.HasPrefix(p, "b/") {
+		return p[2:]
+	}
+	return p
+}
+
+// normalize drops whitespace differences and the trailing brace so that
+// reformatting a declaration is not reported as a signature change
+func normalize(decl string) string {
+	decl = strings.TrimSuffix(strings.TrimSpace(decl), "{")
+	return strings.Join(strings.Fields(decl), " ")
+}
+
+// Summary joins the findings into the text of a BREAKING CHANGE footer
+func Summary(findings []Finding) string {
+	parts := make([]string, 0, len(findings))
+	for _, f := range findings {
+		switch f.Kind {
+		case KindSignature:
+			parts = append(parts, "changes the signature of "+f.Name)
+		case KindFlag:
+			parts = append(parts, "removes the "+f.Name+" flag")
+		case KindConfigKey:
+			parts = append(parts, "removes the "+f.Name+" config key")
+		default:
+			parts = append(parts, "removes or renames "+f.Name)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
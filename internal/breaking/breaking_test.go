@@ -0,0 +1,83 @@
+package breaking
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/internal/llm/provider.go b/internal/llm/provider.go
+index 1111111..2222222 100644
+--- a/internal/llm/provider.go
++++ b/internal/llm/provider.go
+@@ -10,12 +10,12 @@ import (
+-func Register(name string, provider Provider) {
++func Register(name string, provider Provider, aliases ...string) {
+-func GetRegisteredNames() []string {
++func RegisteredNames() []string {
+-func (c *Client) Close() error {
++func (c *Client) Close()  error  {
+-func helper() {}
+-type Options struct {
+ 	var x = 1
+diff --git a/internal/llm/moved.go b/internal/llm/moved.go
+new file mode 100644
+--- /dev/null
++++ b/internal/llm/moved.go
+@@ -0,0 +1,3 @@
++type Options struct {
+diff --git a/cmd/commit.go b/cmd/commit.go
+--- a/cmd/commit.go
++++ b/cmd/commit.go
+@@ -1,4 +1,3 @@
+-	commitCmd.Flags().String("legacy", "", "old flag")
+-	commitCmd.Flags().String(promptFlagName, "", "used to provide additional context to llm")
++	commitCmd.Flags().String(promptFlagName, "", "additional context for the llm")
+diff --git a/internal/config/config.go b/internal/config/config.go
+--- a/internal/config/config.go
++++ b/internal/config/config.go
+@@ -1,3 +1,2 @@
+-	Model string ` + "`json:\"model,omitempty\" yaml:\"model,omitempty\"`" + `
+-	Keys  string ` + "`json:\"keys,omitempty\"`" + `
++	Model string ` + "`json:\"model,omitempty\" yaml:\"model,omitempty\"`" + `
+diff --git a/internal/llm/provider_test.go b/internal/llm/provider_test.go
+--- a/internal/llm/provider_test.go
++++ b/internal/llm/provider_test.go
+@@ -1,1 +0,0 @@
+-func TestRegister(t *testing.T) {}
+`
+
+func TestDetect(t *testing.T) {
+	got := Detect(sampleDiff)
+
+	var names []string
+	kinds := map[string]Kind{}
+	for _, f := range got {
+		names = append(names, f.Name)
+		kinds[f.Name] = f.Kind
+	}
+	want := []string{"--legacy", "keys", "Register", "GetRegisteredNames"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("Detect() names = %v, want %v", names, want)
+	}
+	if kinds["Register"] != KindSignature || kinds["GetRegisteredNames"] != KindRemoved ||
+		kinds["--legacy"] != KindFlag || kinds["keys"] != KindConfigKey {
+		t.Errorf("Detect() kinds = %v", kinds)
+	}
+}
+
+func TestDetectNothing(t *testing.T) {
+	diff := "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-func Old()\n+func New()\n"
+	if got := Detect(diff); len(got) != 0 {
+		t.Errorf("Detect() on non-Go file = %v", got)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	got := Summary([]Finding{
+		{Kind: KindSignature, Name: "Register"},
+		{Kind: KindFlag, Name: "--legacy"},
+	})
+	if want := "changes the signature of Register; removes the --legacy flag"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,256 @@
+// Package changelog groups Conventional Commits into releases and renders
+// them as Keep a Changelog Markdown
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/conventional"
+	"github.com/rshdhere/vibecheck/internal/semver"
+)
+
+// Keep a Changelog section names, in the order they are rendered
+const (
+	Added      = "Added"
+	Changed    = "Changed"
+	Deprecated = "Deprecated"
+	Removed    = "Removed"
+	Fixed      = "Fixed"
+	Security   = "Security"
+)
+
+var sectionOrder = []string{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// Unreleased is the version of changes not yet tagged
+const Unreleased = "Unreleased"
+
+// Commit is a commit to be listed
+type Commit struct {
+	Hash    string
+	Message string
+}
+
+// Entry is one line of the changelog
+type Entry struct {
+	Type        string `json:"type"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	Hash        string `json:"hash"`
+	Breaking    bool   `json:"breaking,omitempty"`
+	// BreakingNote is the BREAKING CHANGE footer, when the commit has one
+	BreakingNote string `json:"breaking_note,omitempty"`
+}
+
+// Section is a Keep a Changelog heading such as Added or Fixed
+type Section struct {
+	Name    string  `json:"name"`
+	Entries []Entry `json:"entries"`
+}
+
+// Release is the changes of one version
+type Release struct {
+	Version string `json:"version"`
+	Date    string `json:"date,omitempty"`
+	// Summary is an optional paragraph introducing the release
+	Summary  string    `json:"summary,omitempty"`
+	Breaking []Entry   `json:"breaking,omitempty"`
+	Sections []Section `json:"sections"`
+}
+
+// Empty reports whether the release lists nothing
+func (r Release) Empty() bool {
+	return len(r.Breaking) == 0 && len(r.Sections) == 0
+}
+
+// Bump returns the version bump the release calls for: major for breaking
+// changes, minor for features and patch for anything else listed
+func (r Release) Bump() semver.Bump {
+	if len(r.Breaking) > 0 {
+		return semver.Major
+	}
+	bump := semver.None
+	for _, s := range r.Sections {
+		for _, e := range s.Entries {
+			if e.Type == "feat" {
+				return semver.Minor
+			}
+			bump = semver.Patch
+		}
+	}
+	return bump
+}
+
+// NewRelease groups commits into sections, sorted by scope. Commits that are
+// not Conventional, and those of types that do not change behaviour such as
+// docs or chore, are left out unless they are breaking.
+func NewRelease(version, date string, commits []Commit) Release {
+	r := Release{Version: version, Date: date}
+	bySection := make(map[string][]Entry)
+	for _, c := range commits {
+		e, ok := parseEntry(c)
+		if !ok {
+			continue
+		}
+		if e.Breaking {
+			r.Breaking = append(r.Breaking, e)
+		}
+		if name := section(e); name != "" {
+			bySection[name] = append(bySection[name], e)
+		}
+	}
+	for _, name := range sectionOrder {
+		entries := bySection[name]
+		if len(entries) == 0 {
+			continue
+		}
+		// Unscoped entries first, then grouped by scope in commit order
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Scope < entries[j].Scope })
+		r.Sections = append(r.Sections, Section{Name: name, Entries: entries})
+	}
+	return r
+}
+
+func parseEntry(c Commit) (Entry, bool) {
+	line, body := conventional.SplitMessage(c.Message)
+	h, ok := conventional.ParseHeader(line)
+	if !ok {
+		return Entry{}, false
+	}
+	e := Entry{Type: h.Type, Scope: h.Scope, Description: h.Description, Hash: c.Hash, Breaking: h.Breaking}
+	for _, l := range strings.Split(body, "\n") {
+		for _, token := range []string{conventional.BreakingFooter, "BREAKING-CHANGE"} {
+			if note, ok := strings.CutPrefix(l, token+":"); ok {
+				e.Breaking, e.BreakingNote = true, strings.TrimSpace(note)
+			}
+		}
+	}
+	return e, true
+}
+
+// section returns the Keep a Changelog section of e, or "" to leave it out
+func section(e Entry) string {
+	description := strings.ToLower(e.Description)
+	switch {
+	case e.Type == "security" || e.Scope == "security":
+		return Security
+	case e.Type == "feat" && strings.HasPrefix(description, "deprecate"):
+		return Deprecated
+	case e.Type == "feat" && (strings.HasPrefix(description, "remove") || strings.HasPrefix(description, "drop")):
+		return Removed
+	case e.Type == "feat":
+		return Added
+	case e.Type == "fix":
+		return Fixed
+	case e.Type == "perf" || e.Type == "refactor" || e.Type == "revert":
+		return Changed
+	default:
+		return ""
+	}
+}
+
+// Header starts a new CHANGELOG.md
+const Header = `# Changelog
+
+All notable changes to this project are documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+`
+
+// Markdown renders releases as Keep a Changelog sections, newest first as given
+func Markdown(releases []Release) string {
+	var b strings.Builder
+	for i, r := range releases {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if r.Version == Unreleased {
+			fmt.Fprintf(&b, "## [%s]\n", Unreleased)
+		} else if r.Date != "" {
+			fmt.Fprintf(&b, "## [%s] - %s\n", r.Version, r.Date)
+		} else {
+			fmt.Fprintf(&b, "## [%s]\n", r.Version)
+		}
+		writeBody(&b, r)
+	}
+	return b.String()
+}
+
+// Notes renders the summary and sections of r without its heading, as in
+// the message of a release tag
+func Notes(r Release) string {
+	var b strings.Builder
+	writeBody(&b, r)
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+func writeBody(b *strings.Builder, r Release) {
+	if r.Summary != "" {
+		fmt.Fprintf(b, "\n%s\n", strings.TrimSpace(r.Summary))
+	}
+	if len(r.Breaking) > 0 {
+		b.WriteString("\n### Breaking changes\n\n")
+		for _, e := range r.Breaking {
+			note := e.BreakingNote
+			if note == "" {
+				note = e.Description
+			}
+			fmt.Fprintf(b, "- %s%s (%s)\n", scopePrefix(e.Scope), note, shortHash(e.Hash))
+		}
+	}
+	for _, s := range r.Sections {
+		fmt.Fprintf(b, "\n### %s\n\n", s.Name)
+		for _, e := range s.Entries {
+			fmt.Fprintf(b, "- %s%s (%s)\n", scopePrefix(e.Scope), e.Description, shortHash(e.Hash))
+		}
+	}
+}
+
+func scopePrefix(scope string) string {
+	if scope == "" {
+		return ""
+	}
+	return "**" + scope + ":** "
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// Prepend adds releases, rendered with Markdown, above the first release of
+// an existing changelog, replacing its Unreleased section. An empty existing
+// changelog gets the standard Header.
+func Prepend(existing, releases string) string {
+	if strings.TrimSpace(existing) == "" {
+		return Header + "\n" + releases
+	}
+	lines := strings.SplitAfter(existing, "\n")
+	first := len(lines)
+	for i, l := range lines {
+		if strings.HasPrefix(l, "## ") {
+			first = i
+			break
+		}
+	}
+	head := strings.Join(lines[:first], "")
+	rest := lines[first:]
+	if len(rest) > 0 && strings.HasPrefix(rest[0], "## ["+Unreleased+"]") {
+		end := 1
+		for end < len(rest) && !strings.HasPrefix(rest[end], "## ") {
+			end++
+		}
+		rest = rest[end:]
+	}
+	if !strings.HasSuffix(head, "\n\n") {
+		head = strings.TrimRight(head, "\n") + "\n\n"
+	}
+	out := head + strings.TrimRight(releases, "\n") + "\n"
+	if len(rest) > 0 {
+		out += "\n" + strings.Join(rest, "")
+	}
+	return out
+}
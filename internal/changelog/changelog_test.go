@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/semver"
+)
+
+func TestNewRelease(t *testing.T) {
+	commits := []Commit{
+		{Hash: "1111111aaaa", Message: "feat(parser): add streaming mode"},
+		{Hash: "2222222bbbb", Message: "fix: handle empty input"},
+		{Hash: "3333333cccc", Message: "feat: add json output"},
+		{Hash: "4444444dddd", Message: "chore: bump deps"},
+		{Hash: "5555555eeee", Message: "feat(api)!: remove v1 endpoints\n\nBREAKING CHANGE: clients must use /v2"},
+		{Hash: "6666666ffff", Message: "wip"},
+		{Hash: "7777777aaaa", Message: "perf(parser): avoid copies"},
+		{Hash: "8888888bbbb", Message: "fix(security): escape shell arguments"},
+	}
+	r := NewRelease("1.2.0", "2025-01-02", commits)
+
+	wantBreaking := []Entry{{Type: "feat", Scope: "api", Description: "remove v1 endpoints", Hash: "5555555eeee", Breaking: true, BreakingNote: "clients must use /v2"}}
+	if !reflect.DeepEqual(r.Breaking, wantBreaking) {
+		t.Errorf("Breaking = %+v, want %+v", r.Breaking, wantBreaking)
+	}
+
+	var names []string
+	for _, s := range r.Sections {
+		names = append(names, s.Name)
+	}
+	if want := []string{Added, Changed, Removed, Fixed, Security}; !reflect.DeepEqual(names, want) {
+		t.Errorf("sections = %v, want %v", names, want)
+	}
+	// Unscoped entries come first, then by scope
+	added := r.Sections[0].Entries
+	if len(added) != 2 || added[0].Description != "add json output" || added[1].Scope != "parser" {
+		t.Errorf("Added = %+v", added)
+	}
+}
+
+func TestReleaseBump(t *testing.T) {
+	tests := []struct {
+		messages []string
+		want     semver.Bump
+	}{
+		{messages: []string{"docs: fix typo", "chore: bump deps"}, want: semver.None},
+		{messages: []string{"fix: handle empty input", "perf: avoid copies"}, want: semver.Patch},
+		{messages: []string{"fix: handle empty input", "feat: add json output"}, want: semver.Minor},
+		{messages: []string{"feat: add json output", "refactor!: rename flags"}, want: semver.Major},
+		{messages: []string{"fix: escape input\n\nBREAKING CHANGE: quotes are no longer accepted"}, want: semver.Major},
+	}
+	for _, tt := range tests {
+		var commits []Commit
+		for _, m := range tt.messages {
+			commits = append(commits, Commit{Hash: "1234567", Message: m})
+		}
+		if got := NewRelease(Unreleased, "", commits).Bump(); got != tt.want {
+			t.Errorf("Bump(%q) = %s, want %s", tt.messages, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	releases := []Release{
+		NewRelease(Unreleased, "", []Commit{{Hash: "abcdef123", Message: "fix: handle empty input"}}),
+		NewRelease("1.0.0", "2025-01-02", []Commit{
+			{Hash: "1234567890", Message: "feat(api)!: drop v1"},
+			{Hash: "0987654321", Message: "feat: add json output"},
+		}),
+	}
+	releases[1].Summary = "The first stable release."
+	want := `## [Unreleased]
+
+### Fixed
+
+- handle empty input (abcdef1)
+
+## [1.0.0] - 2025-01-02
+
+The first stable release.
+
+### Breaking changes
+
+- **api:** drop v1 (1234567)
+
+### Added
+
+- add json output (0987654)
+
+### Removed
+
+- **api:** drop v1 (1234567)
+`
+	if got := Markdown(releases); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	release := "## [1.1.0] - 2025-02-01\n\n### Fixed\n\n- handle empty input (abcdef1)\n"
+
+	if got := Prepend("", release); got != Header+"\n"+release {
+		t.Errorf("Prepend(empty) = %q", got)
+	}
+
+	existing := "# Changelog\n\nIntro.\n\n## [Unreleased]\n\n### Added\n\n- old (1111111)\n\n## [1.0.0] - 2025-01-02\n\n- first\n"
+	want := "# Changelog\n\nIntro.\n\n" + release + "\n## [1.0.0] - 2025-01-02\n\n- first\n"
+	if got := Prepend(existing, release); got != want {
+		t.Errorf("Prepend() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := Prepend("# Changelog\n", release); got != "# Changelog\n\n"+release {
+		t.Errorf("Prepend(no releases) = %q", got)
+	}
+}
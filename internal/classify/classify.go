@@ -0,0 +1,301 @@
+// Package classify recognizes trivial staged changes, such as pure formatting
+// or pure renames, that can be described without asking a provider
+package classify
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/patch"
+)
+
+// Kind is the class of a whole change
+type Kind string
+
+const (
+	KindNone       Kind = ""
+	KindFormatting Kind = "formatting"
+	KindComments   Kind = "comments"
+	KindCosmetic   Kind = "formatting and comments"
+	KindRename     Kind = "rename"
+	KindDelete     Kind = "delete"
+	KindTest       Kind = "test"
+)
+
+// fileKind is the class of a single file's change
+type fileKind int
+
+const (
+	fileCode fileKind = iota
+	fileWhitespace
+	fileComments
+	fileRename
+	fileDelete
+)
+
+// Result is the outcome of Classify. Message is empty for KindNone.
+type Result struct {
+	Kind    Kind
+	Message string
+}
+
+// Classify looks at the staged diff split into files, and the same diff taken
+// with whitespace ignored (git diff -w), which omits whitespace-only files.
+func Classify(files, ignoringWhitespace []patch.File) Result {
+	if len(files) == 0 {
+		return Result{}
+	}
+
+	ws := make(map[string]patch.File, len(ignoringWhitespace))
+	for _, f := range ignoringWhitespace {
+		ws[f.Path] = f
+	}
+
+	counts := map[fileKind]int{}
+	tests, added := 0, 0
+	for _, f := range files {
+		counts[kindOf(f, ws)]++
+		if IsTest(f.Path) {
+			tests++
+		}
+		if f.New {
+			added++
+		}
+	}
+
+	n := len(files)
+	switch {
+	case counts[fileDelete] == n:
+		return Result{KindDelete, listMessage("chore: remove", "files", files, func(f patch.File) string { return f.Path })}
+	case counts[fileRename] == n:
+		return Result{KindRename, renameMessage(files)}
+	case counts[fileWhitespace] == n:
+		return Result{KindFormatting, countMessage("style: reformat", "files", files)}
+	case counts[fileComments] == n:
+		return Result{KindComments, countMessage("docs: update comments in", "files", files)}
+	case counts[fileWhitespace]+counts[fileComments] == n:
+		return Result{KindCosmetic, "chore: non-functional formatting or comment update"}
+	case tests == n && added == n:
+		return Result{KindTest, countMessage("test: add", "test files", files)}
+	case tests == n:
+		return Result{KindTest, countMessage("test: update", "test files", files)}
+	}
+	return Result{}
+}
+
+func kindOf(f patch.File, ws map[string]patch.File) fileKind {
+	switch {
+	case f.Removed:
+		return fileDelete
+	case modeChanged(f):
+		// chmod +x changes what the file does, even without content changes
+		return fileCode
+	case f.OldPath != "" && f.OldPath != f.Path && f.Changed() == 0 && !f.Binary:
+		return fileRename
+	case f.New || f.Binary || f.Changed() == 0:
+		return fileCode
+	case significantWhitespace(f.Path):
+		// Indentation is syntax here, so only the full diff tells
+		if onlyComments(f.Path, f.Text) {
+			return fileComments
+		}
+		return fileCode
+	}
+
+	w, ok := ws[f.Path]
+	if !ok || w.Changed() == 0 {
+		return fileWhitespace
+	}
+	if onlyComments(f.Path, w.Text) {
+		return fileComments
+	}
+	return fileCode
+}
+
+// modeChanged reports whether the section changes the file mode
+func modeChanged(f patch.File) bool {
+	header, _, _ := strings.Cut(f.Text, "\n@@")
+	return strings.Contains(header, "\nold mode ") || strings.Contains(header, "\nnew mode ")
+}
+
+// significantWhitespace reports whether indentation is part of the syntax of
+// file, as in Python, YAML and Makefiles
+func significantWhitespace(file string) bool {
+	base := path.Base(file)
+	switch base {
+	case "Makefile", "GNUmakefile", "makefile":
+		return true
+	}
+	switch strings.ToLower(path.Ext(base)) {
+	case ".py", ".pyi", ".yaml", ".yml", ".mk", ".haml", ".pug", ".sass", ".styl", ".coffee", ".nim":
+		return true
+	}
+	return false
+}
+
+// onlyComments reports whether every changed line in section is a comment or
+// blank. Lines that open or close a block comment around lines the change does
+// not touch, such as "/*" and "*/" wrapping existing code, are code.
+func onlyComments(file, section string) bool {
+	markers := commentMarkers(file)
+	if len(markers) == 0 {
+		return false
+	}
+	opener, closer := blockMarkers(markers)
+	// open tracks, per side of the diff, a block comment opened by a changed line
+	open := map[byte]bool{}
+	inHunk, changed := false, false
+	for _, line := range strings.Split(section, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			if open['+'] || open['-'] {
+				return false
+			}
+			inHunk = true
+			continue
+		}
+		if !inHunk || line == "" {
+			continue
+		}
+		sign := line[0]
+		if sign == ' ' && (open['+'] || open['-']) {
+			return false
+		}
+		if sign != '+' && sign != '-' {
+			continue
+		}
+		changed = true
+		body := strings.TrimSpace(line[1:])
+		if open[sign] {
+			if i := strings.Index(body, closer); i >= 0 {
+				open[sign] = false
+				if strings.TrimSpace(body[i+len(closer):]) != "" {
+					return false
+				}
+			}
+			continue
+		}
+		switch {
+		case body == "":
+		case opener != "" && strings.HasPrefix(body, opener):
+			rest := body[len(opener):]
+			if i := strings.Index(rest, closer); i < 0 {
+				open[sign] = true
+			} else if strings.TrimSpace(rest[i+len(closer):]) != "" {
+				return false
+			}
+		case closer != "" && strings.HasPrefix(body, closer):
+			return false
+		case !hasPrefix(body, markers):
+			return false
+		}
+	}
+	return changed && !open['+'] && !open['-']
+}
+
+// blockMarkers returns the block comment delimiters among markers, if any
+func blockMarkers(markers []string) (string, string) {
+	for _, m := range markers {
+		switch m {
+		case "/*":
+			return "/*", "*/"
+		case "<!--":
+			return "<!--", "-->"
+		}
+	}
+	return "", ""
+}
+
+func hasPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	slashComments = []string{"//", "/*", "*/", "* ", "*\t"}
+	hashComments  = []string{"#"}
+)
+
+// commentLanguages maps extensions to the line prefixes that start a comment
+var commentLanguages = map[string][]string{
+	".go": slashComments, ".c": slashComments, ".h": slashComments, ".cc": slashComments,
+	".cpp": slashComments, ".hpp": slashComments, ".java": slashComments, ".kt": slashComments,
+	".js": slashComments, ".jsx": slashComments, ".ts": slashComments, ".tsx": slashComments,
+	".rs": slashComments, ".swift": slashComments, ".cs": slashComments, ".scala": slashComments,
+	".php": append([]string{"#"}, slashComments...), ".css": {"/*", "*/", "* "}, ".scss": slashComments,
+	".py": hashComments, ".rb": hashComments, ".sh": hashComments, ".bash": hashComments,
+	".yaml": hashComments, ".yml": hashComments, ".toml": hashComments, ".pl": hashComments,
+	".r": hashComments, ".ex": hashComments, ".exs": hashComments,
+	".sql": {"--"}, ".lua": {"--"}, ".hs": {"--"},
+	".html": {"<!--", "-->"}, ".xml": {"<!--", "-->"}, ".vue": {"<!--", "-->", "//"},
+}
+
+func commentMarkers(file string) []string {
+	switch base := path.Base(file); base {
+	case "Makefile", "Dockerfile", ".gitignore", ".vibecheckignore":
+		return hashComments
+	default:
+		return commentLanguages[strings.ToLower(path.Ext(base))]
+	}
+}
+
+// IsTest reports whether file is a test by common naming conventions
+func IsTest(file string) bool {
+	base := path.Base(file)
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	switch {
+	case strings.HasSuffix(base, "_test.go"):
+		return true
+	case strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"):
+		return true
+	case ext == ".py" && (strings.HasPrefix(stem, "test_") || strings.HasSuffix(stem, "_test")):
+		return true
+	case (strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests")) &&
+		(ext == ".java" || ext == ".kt" || ext == ".cs" || ext == ".swift"):
+		return true
+	case ext == ".rb" && strings.HasSuffix(stem, "_spec"):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if dir == "__tests__" || dir == "tests" || dir == "testdata" {
+			return true
+		}
+	}
+	return false
+}
+
+// countMessage names the single file or counts several
+func countMessage(prefix, noun string, files []patch.File) string {
+	if len(files) == 1 {
+		return fmt.Sprintf("%s %s", prefix, files[0].Path)
+	}
+	return fmt.Sprintf("%s %d %s", prefix, len(files), noun)
+}
+
+// listMessage is countMessage with a bullet per file for several files
+func listMessage(prefix, noun string, files []patch.File, item func(patch.File) string) string {
+	msg := countMessage(prefix, noun, files)
+	if len(files) == 1 {
+		return msg
+	}
+	var b strings.Builder
+	b.WriteString(msg + "\n")
+	for _, f := range files {
+		b.WriteString("\n- " + item(f))
+	}
+	return b.String()
+}
+
+func renameMessage(files []patch.File) string {
+	if len(files) == 1 {
+		return fmt.Sprintf("refactor: rename %s to %s", files[0].OldPath, files[0].Path)
+	}
+	return listMessage("refactor: rename", "files", files, func(f patch.File) string {
+		return f.OldPath + " -> " + f.Path
+	})
+}
@@ -0,0 +1,146 @@
+package classify
+
+import (
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/patch"
+)
+
+func TestClassify(t *testing.T) {
+	goSection := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n"
+
+	tests := []struct {
+		name  string
+		files []patch.File
+		ws    []patch.File
+		want  Result
+	}{
+		{
+			name:  "whitespace only",
+			files: []patch.File{{Path: "a.go", Added: 2, Deleted: 2}},
+			want:  Result{KindFormatting, "style: reformat a.go"},
+		},
+		{
+			name:  "comments only",
+			files: []patch.File{{Path: "a.go", Added: 1, Deleted: 1}},
+			ws:    []patch.File{{Path: "a.go", Added: 1, Deleted: 1, Text: goSection + "-// old words\n+// new words\n"}},
+			want:  Result{KindComments, "docs: update comments in a.go"},
+		},
+		{
+			name: "formatting and comments",
+			files: []patch.File{
+				{Path: "a.go", Added: 1, Deleted: 1},
+				{Path: "b.js", Added: 1},
+			},
+			ws: []patch.File{
+				{Path: "a.go", Added: 1, Deleted: 1, Text: goSection + "-/* a */\n+/* b */\n"},
+			},
+			want: Result{KindCosmetic, "chore: non-functional formatting or comment update"},
+		},
+		{
+			name:  "code",
+			files: []patch.File{{Path: "a.go", Added: 1, Deleted: 1}},
+			ws:    []patch.File{{Path: "a.go", Added: 1, Deleted: 1, Text: goSection + "-x := 1\n+x := 2\n"}},
+		},
+		{
+			name:  "pointer assignment is not a comment",
+			files: []patch.File{{Path: "a.go", Added: 1}},
+			ws:    []patch.File{{Path: "a.go", Added: 1, Text: goSection + "+*p = 2\n"}},
+		},
+		{
+			name: "renames",
+			files: []patch.File{
+				{Path: "internal/x/new.go", OldPath: "internal/x/old.go"},
+				{Path: "docs/b.md", OldPath: "b.md"},
+			},
+			want: Result{KindRename, "refactor: rename 2 files\n\n- internal/x/old.go -> internal/x/new.go\n- b.md -> docs/b.md"},
+		},
+		{
+			name:  "single delete",
+			files: []patch.File{{Path: "legacy.go", Removed: true, Deleted: 40}},
+			want:  Result{KindDelete, "chore: remove legacy.go"},
+		},
+		{
+			name: "new tests",
+			files: []patch.File{
+				{Path: "cmd/a_test.go", New: true, Added: 10},
+				{Path: "web/__tests__/b.js", New: true, Added: 5},
+			},
+			want: Result{KindTest, "test: add 2 test files"},
+		},
+		{
+			name:  "changed test",
+			files: []patch.File{{Path: "tests/test_api.py", Added: 3, Text: "@@ -1 +1 @@\n+assert x\n"}},
+			ws:    []patch.File{{Path: "tests/test_api.py", Added: 3, Text: "@@ -1 +1 @@\n+assert x\n"}},
+			want:  Result{KindTest, "test: update tests/test_api.py"},
+		},
+		{
+			name:  "mode change",
+			files: []patch.File{{Path: "s.sh", OldPath: "s.sh", Text: "diff --git a/s.sh b/s.sh\nold mode 100644\nnew mode 100755\n"}},
+		},
+		{
+			name:  "renamed and made executable",
+			files: []patch.File{{Path: "b.sh", OldPath: "a.sh", Text: "diff --git a/a.sh b/b.sh\nold mode 100644\nnew mode 100755\nsimilarity index 100%\nrename from a.sh\nrename to b.sh\n"}},
+		},
+		{
+			name: "python dedent",
+			files: []patch.File{{Path: "p.py", Added: 1, Deleted: 1,
+				Text: "diff --git a/p.py b/p.py\n--- a/p.py\n+++ b/p.py\n@@ -1,2 +1,2 @@\n if a:\n-    b()\n+b()\n"}},
+		},
+		{
+			name: "yaml comment",
+			files: []patch.File{{Path: "ci.yml", Added: 1, Deleted: 1,
+				Text: "diff --git a/ci.yml b/ci.yml\n--- a/ci.yml\n+++ b/ci.yml\n@@ -1,2 +1,2 @@\n-# old\n+  # new\n on: push\n"}},
+			want: Result{KindComments, "docs: update comments in ci.yml"},
+		},
+		{
+			name:  "code wrapped in a block comment",
+			files: []patch.File{{Path: "a.go", Added: 2}},
+			ws:    []patch.File{{Path: "a.go", Added: 2, Text: goSection + "+/*\n x := f()\n+*/\n"}},
+		},
+		{
+			name:  "code after a block comment",
+			files: []patch.File{{Path: "a.go", Added: 1}},
+			ws:    []patch.File{{Path: "a.go", Added: 1, Text: goSection + "+/* note */ x := f()\n"}},
+		},
+		{
+			name:  "new block comment",
+			files: []patch.File{{Path: "a.go", Added: 3}},
+			ws:    []patch.File{{Path: "a.go", Added: 3, Text: goSection + "+/*\n+Package a does things.\n+*/\n package a\n"}},
+			want:  Result{KindComments, "docs: update comments in a.go"},
+		},
+		{
+			name: "rename mixed with code",
+			files: []patch.File{
+				{Path: "b.go", OldPath: "a.go"},
+				{Path: "c.go", New: true, Added: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.files, tt.ws); got != tt.want {
+				t.Errorf("Classify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTest(t *testing.T) {
+	for path, want := range map[string]bool{
+		"internal/git/diff_test.go":        true,
+		"src/button.test.tsx":              true,
+		"src/api.spec.ts":                  true,
+		"test_models.py":                   true,
+		"src/main/java/FooTest.java":       true,
+		"spec/user_spec.rb":                true,
+		"internal/redact/testdata/key.txt": true,
+		"cmd/commit.go":                    false,
+		"latest.go":                        false,
+		"src/Contest.java":                 false,
+	} {
+		if got := IsTest(path); got != want {
+			t.Errorf("IsTest(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
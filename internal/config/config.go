@@ -8,8 +8,139 @@ import (
 	"path/filepath"
 )
 
+// Config holds every setting vibecheck understands. The same struct is read from
+// the global ~/.vibecheck.json and from the repository's .vibecheck.yaml
 type Config struct {
-	DefaultProvider string `json:"default_provider"`
+	DefaultProvider string   `json:"default_provider,omitempty" yaml:"default_provider,omitempty"`
+	Model           string   `json:"model,omitempty" yaml:"model,omitempty"`
+	Keys            string   `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Style           string   `json:"style,omitempty" yaml:"style,omitempty"`
+	Language        string   `json:"language,omitempty" yaml:"language,omitempty"`
+	Scopes          []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	Ignore          []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+	Instructions    string   `json:"instructions,omitempty" yaml:"instructions,omitempty"`
+	Trailers        []string `json:"trailers,omitempty" yaml:"trailers,omitempty"`
+
+	// Signoff adds a Signed-off-by trailer for the committer, as git commit -s does
+	Signoff bool `json:"signoff,omitempty" yaml:"signoff,omitempty"`
+	// Coauthors maps short aliases of pair partners to "Name <email>"
+	Coauthors map[string]string `json:"coauthors,omitempty" yaml:"coauthors,omitempty"`
+
+	// ScopeMap maps path globs such as internal/llm/** to canonical scopes
+	ScopeMap   map[string]string `json:"scope_map,omitempty" yaml:"scope_map,omitempty"`
+	Codeowners bool              `json:"codeowners,omitempty" yaml:"codeowners,omitempty"`
+
+	Issue IssueConfig `json:"issue,omitempty" yaml:"issue,omitempty"`
+
+	Provenance ProvenanceConfig `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+
+	Redact RedactConfig `json:"redact,omitempty" yaml:"redact,omitempty"`
+
+	Diff DiffConfig `json:"diff,omitempty" yaml:"diff,omitempty"`
+
+	Hook HookConfig `json:"hook,omitempty" yaml:"hook,omitempty"`
+
+	PR PRConfig `json:"pr,omitempty" yaml:"pr,omitempty"`
+
+	Release ReleaseConfig `json:"release,omitempty" yaml:"release,omitempty"`
+
+	// ForceLLM sends every change to the provider, even those vibecheck can
+	// describe locally such as dependency bumps and pure renames
+	ForceLLM bool `json:"force_llm,omitempty" yaml:"force_llm,omitempty"`
+
+	Profile  string              `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Profiles map[string]*Profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// IssueConfig controls how issue keys are pulled from the branch name and
+// where they are placed in the message
+type IssueConfig struct {
+	// Patterns are regular expressions tried in order against the branch name
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+	// Placement is one of prefix, scope or footer
+	Placement string `json:"placement,omitempty" yaml:"placement,omitempty"`
+	// Footer is the trailer token used by the footer placement, Refs by default
+	Footer string `json:"footer,omitempty" yaml:"footer,omitempty"`
+	// Required refuses to commit when no key is found
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// ProvenanceConfig records which commits had their message written by vibecheck
+type ProvenanceConfig struct {
+	// Trailer adds "Generated-by: vibecheck/<version> (<provider>/<model>)"
+	Trailer bool `json:"trailer,omitempty" yaml:"trailer,omitempty"`
+	// Notes attaches a note under refs/notes/vibecheck to every generated commit
+	Notes bool `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+// DiffConfig controls how the staged diff handed to the provider is produced
+type DiffConfig struct {
+	// Context is the number of context lines, git's default of 3 when unset
+	Context *int `json:"context,omitempty" yaml:"context,omitempty"`
+	// FunctionContext includes the whole function around each change
+	FunctionContext bool `json:"function_context,omitempty" yaml:"function_context,omitempty"`
+	// Whitespace is one of all, change, eol or blank-lines
+	Whitespace string `json:"whitespace,omitempty" yaml:"whitespace,omitempty"`
+	// Renames is off, or a similarity threshold such as 50%
+	Renames string `json:"renames,omitempty" yaml:"renames,omitempty"`
+	// Copies is on, harder, or a similarity threshold such as 75%
+	Copies string `json:"copies,omitempty" yaml:"copies,omitempty"`
+	// Algorithm is one of myers, minimal, patience or histogram
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	// Submodule is one of short, log or diff
+	Submodule string `json:"submodule,omitempty" yaml:"submodule,omitempty"`
+	// StatOnly sends a diffstat instead of the patch, so no code leaves the machine
+	StatOnly bool `json:"stat_only,omitempty" yaml:"stat_only,omitempty"`
+}
+
+// HookConfig selects which commits the prepare-commit-msg hook writes a
+// message for besides those started without one
+type HookConfig struct {
+	// Merge replaces git's "Merge branch" message
+	Merge bool `json:"merge,omitempty" yaml:"merge,omitempty"`
+	// Squash replaces the message prepared by git merge --squash
+	Squash bool `json:"squash,omitempty" yaml:"squash,omitempty"`
+	// Amend rewrites the message of an amended commit from its full diff
+	Amend bool `json:"amend,omitempty" yaml:"amend,omitempty"`
+}
+
+// PRConfig controls how vibecheck pr writes pull requests
+type PRConfig struct {
+	// Base is the branch pull requests target, main when unset
+	Base string `json:"base,omitempty" yaml:"base,omitempty"`
+	// Template is a Markdown file, relative to the repository root, whose
+	// sections the description fills in. The repository's
+	// .github/pull_request_template.md is used when unset.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// ReleaseConfig controls how vibecheck release computes versions
+type ReleaseConfig struct {
+	// TagPrefix comes before the version in release tags, v by default
+	TagPrefix string `json:"tag_prefix,omitempty" yaml:"tag_prefix,omitempty"`
+	// MajorOnZero lets breaking changes take a 0.x version to 1.0.0; by
+	// default they bump the minor until the first stable release
+	MajorOnZero bool `json:"major_on_zero,omitempty" yaml:"major_on_zero,omitempty"`
+}
+
+// RedactConfig controls the scan for secrets and personal data that runs
+// before a diff is sent to a cloud provider
+type RedactConfig struct {
+	// Disabled turns the scan off entirely
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// Block refuses to commit when a secret is found instead of asking
+	Block bool `json:"block,omitempty" yaml:"block,omitempty"`
+	// Rules are extra regular expressions by name, treated as secrets
+	Rules map[string]string `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// Allow are regular expressions for values that must never be redacted
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	// AllowPaths are globs of files that are not scanned
+	AllowPaths []string `json:"allow_paths,omitempty" yaml:"allow_paths,omitempty"`
+}
+
+// Default returns the built-in configuration used when nothing else is set
+func Default() *Config {
+	return &Config{DefaultProvider: "openai"}
 }
 
 // getConfigPath returns the path to the config file
@@ -30,7 +161,7 @@ func Load() (*Config, error) {
 
 	// If config doesn't exist, return default config
 	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
-		return &Config{DefaultProvider: "openai"}, nil
+		return Default(), nil
 	}
 
 	data, err := os.ReadFile(path)
@@ -70,8 +201,11 @@ func GetDefaultProvider() string {
 	return cfg.DefaultProvider
 }
 
-// SetDefaultProvider saves the default provider to config
+// SetDefaultProvider saves the default provider to config, keeping every other setting
 func SetDefaultProvider(provider string) error {
-	cfg := &Config{DefaultProvider: provider}
-	return Save(cfg)
+	path, err := getConfigPath()
+	if err != nil {
+		return err
+	}
+	return SetValue(path, "default_provider", provider)
 }
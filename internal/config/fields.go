@@ -0,0 +1,263 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind describes the type of value a configuration key accepts
+type Kind string
+
+const (
+	KindString Kind = "string"
+	KindBool   Kind = "bool"
+	KindInt    Kind = "int"
+	KindList   Kind = "list"
+	KindMap    Kind = "map"
+)
+
+// Field describes a single configurable leaf key
+type Field struct {
+	Key  string
+	Kind Kind
+}
+
+// ErrUnknownKey is returned when a dotted key does not name a configuration value
+var ErrUnknownKey = fmt.Errorf("unknown config key")
+
+// Fields lists every leaf key of cfg in dotted form, sorted alphabetically.
+// Entries of keyed sections (such as profiles) are only listed when present in cfg.
+func Fields(cfg *Config) []Field {
+	var fields []Field
+	collectFields(reflect.ValueOf(cfg).Elem(), "", &fields)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+func collectFields(v reflect.Value, prefix string, fields *[]Field) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := fieldName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			collectFields(v.Field(i), joinKey(prefix, name), fields)
+		}
+	case isSection(v.Type()):
+		keys := v.MapKeys()
+		for _, k := range keys {
+			collectFields(v.MapIndex(k).Elem(), joinKey(prefix, k.String()), fields)
+		}
+	default:
+		*fields = append(*fields, Field{Key: prefix, Kind: kindOf(v.Type())})
+	}
+}
+
+// Get returns the value stored under a dotted key
+func (c *Config) Get(key string) (any, error) {
+	v, err := lookup(reflect.ValueOf(c).Elem(), key, false)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// Set parses raw according to the type of key and stores it in the config.
+// Lists are comma separated and maps are written as comma separated key=value pairs.
+func (c *Config) Set(key, raw string) error {
+	v, err := lookup(reflect.ValueOf(c).Elem(), key, true)
+	if err != nil {
+		return err
+	}
+	parsed, err := parseValue(v.Type(), raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	v.Set(parsed)
+	return nil
+}
+
+// KindOf reports the kind of value a dotted key accepts
+func KindOf(key string) (Kind, error) {
+	v, err := lookup(reflect.ValueOf(&Config{}).Elem(), key, true)
+	if err != nil {
+		return "", err
+	}
+	return kindOf(v.Type()), nil
+}
+
+// FormatValue renders a configuration value the same way Set expects to read it
+func FormatValue(value any) string {
+	switch val := value.(type) {
+	case []string:
+		return strings.Join(val, ",")
+	case map[string]string:
+		pairs := make([]string, 0, len(val))
+		for k, v := range val {
+			pairs = append(pairs, k+"="+v)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return ""
+			}
+			return FormatValue(v.Elem().Interface())
+		}
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// lookup walks a dotted key through structs and keyed sections.
+// When create is true missing section entries are allocated on the way.
+func lookup(v reflect.Value, key string, create bool) (reflect.Value, error) {
+	if key == "" {
+		return reflect.Value{}, ErrUnknownKey
+	}
+	parts := strings.Split(key, ".")
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		switch {
+		case v.Kind() == reflect.Struct:
+			field, ok := structField(v, part)
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+			}
+			v = field
+		case isSection(v.Type()):
+			entry := v.MapIndex(reflect.ValueOf(part))
+			if !entry.IsValid() || entry.IsNil() {
+				if !create {
+					return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+				}
+				if v.IsNil() {
+					v.Set(reflect.MakeMap(v.Type()))
+				}
+				entry = reflect.New(v.Type().Elem().Elem())
+				v.SetMapIndex(reflect.ValueOf(part), entry)
+			}
+			v = entry.Elem()
+		default:
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+		}
+	}
+	if v.Kind() == reflect.Struct || isSection(v.Type()) {
+		return reflect.Value{}, fmt.Errorf("%w: %s is a section, not a value", ErrUnknownKey, key)
+	}
+	return v, nil
+}
+
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if fieldName(t.Field(i)) == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// fieldName returns the key a struct field is exposed under, taken from its yaml tag
+func fieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag := f.Tag.Get("yaml")
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
+
+// isSection reports whether t is a map of named sub-configurations, e.g. profiles
+func isSection(t reflect.Type) bool {
+	return t.Kind() == reflect.Map &&
+		t.Key().Kind() == reflect.String &&
+		t.Elem().Kind() == reflect.Pointer &&
+		t.Elem().Elem().Kind() == reflect.Struct
+}
+
+func kindOf(t reflect.Type) Kind {
+	switch t.Kind() {
+	case reflect.Pointer:
+		// Pointers tell an explicit zero apart from an unset value
+		return kindOf(t.Elem())
+	case reflect.Bool:
+		return KindBool
+	case reflect.Int:
+		return KindInt
+	case reflect.Slice:
+		return KindList
+	case reflect.Map:
+		return KindMap
+	default:
+		return KindString
+	}
+}
+
+func parseValue(t reflect.Type, raw string) (reflect.Value, error) {
+	if t.Kind() == reflect.Pointer {
+		v, err := parseValue(t.Elem(), raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v)
+		return p, nil
+	}
+	switch kindOf(t) {
+	case KindBool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %q", raw)
+		}
+		return reflect.ValueOf(b), nil
+	case KindInt:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid int %q", raw)
+		}
+		return reflect.ValueOf(n), nil
+	case KindList:
+		return reflect.ValueOf(splitList(raw)), nil
+	case KindMap:
+		m := map[string]string{}
+		for _, pair := range splitList(raw) {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("invalid map entry %q, want key=value", pair)
+			}
+			m[strings.TrimSpace(k)] = strings.TrimSpace(val)
+		}
+		return reflect.ValueOf(m), nil
+	default:
+		return reflect.ValueOf(raw).Convert(t), nil
+	}
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetAndGet(t *testing.T) {
+	cfg := &Config{}
+
+	if err := cfg.Set("scopes", "cli, llm,,git"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	value, err := cfg.Get("scopes")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := FormatValue(value); got != "cli,llm,git" {
+		t.Errorf("Get(scopes) = %q, want cli,llm,git", got)
+	}
+
+	if err := cfg.Set("model", "gpt-4o"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if cfg.Model != "gpt-4o" {
+		t.Errorf("Model = %q, want gpt-4o", cfg.Model)
+	}
+}
+
+func TestSetUnknownKey(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("does.not.exist", "x"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Set() error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields(&Config{})
+	kinds := map[string]Kind{}
+	for _, f := range fields {
+		kinds[f.Key] = f.Kind
+	}
+	if kinds["default_provider"] != KindString {
+		t.Errorf("default_provider kind = %q, want string", kinds["default_provider"])
+	}
+	if kinds["trailers"] != KindList {
+		t.Errorf("trailers kind = %q, want list", kinds["trailers"])
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	if got := FormatValue(map[string]string{"b": "2", "a": "1"}); got != "a=1,b=2" {
+		t.Errorf("FormatValue(map) = %q, want a=1,b=2", got)
+	}
+	if got := FormatValue(true); got != "true" {
+		t.Errorf("FormatValue(bool) = %q, want true", got)
+	}
+	if got := FormatValue((*int)(nil)); got != "" {
+		t.Errorf("FormatValue(unset int) = %q, want empty", got)
+	}
+	zero := 0
+	if got := FormatValue(&zero); got != "0" {
+		t.Errorf("FormatValue(&0) = %q, want 0", got)
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/glob"
+)
+
+// Profile bundles settings that are switched together, e.g. "work" and "personal".
+// A profile is picked with --profile, VIBECHECK_PROFILE or the profile key, or
+// automatically when the repository matches one of its remote or path globs.
+type Profile struct {
+	DefaultProvider string   `json:"default_provider,omitempty" yaml:"default_provider,omitempty"`
+	Model           string   `json:"model,omitempty" yaml:"model,omitempty"`
+	Keys            string   `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Style           string   `json:"style,omitempty" yaml:"style,omitempty"`
+	Language        string   `json:"language,omitempty" yaml:"language,omitempty"`
+	Instructions    string   `json:"instructions,omitempty" yaml:"instructions,omitempty"`
+	Trailers        []string `json:"trailers,omitempty" yaml:"trailers,omitempty"`
+	MatchRemote     []string `json:"match_remote,omitempty" yaml:"match_remote,omitempty"`
+	MatchPath       []string `json:"match_path,omitempty" yaml:"match_path,omitempty"`
+}
+
+// layer returns the profile as a config layer that can be merged
+func (p *Profile) layer() *Config {
+	return &Config{
+		DefaultProvider: p.DefaultProvider,
+		Model:           p.Model,
+		Keys:            p.Keys,
+		Style:           p.Style,
+		Language:        p.Language,
+		Instructions:    p.Instructions,
+		Trailers:        p.Trailers,
+	}
+}
+
+// matchProfile returns the first profile, by name, whose globs match one of
+// the remotes or the repository root, along with the pattern that matched
+func matchProfile(profiles map[string]*Profile, root string, remotes []string) (string, string) {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	normalized := make([]string, len(remotes))
+	for i, remote := range remotes {
+		normalized[i] = NormalizeRemote(remote)
+	}
+
+	for _, name := range names {
+		p := profiles[name]
+		if p == nil {
+			continue
+		}
+		for _, pattern := range p.MatchRemote {
+			for _, remote := range normalized {
+				if glob.Match(pattern, remote) {
+					return name, "remote " + pattern
+				}
+			}
+		}
+		if root == "" {
+			continue
+		}
+		for _, pattern := range p.MatchPath {
+			if glob.Match(expandHome(pattern), filepath.ToSlash(root)) {
+				return name, "path " + pattern
+			}
+		}
+	}
+	return "", ""
+}
+
+// NormalizeRemote reduces ssh and https remote URLs to host/path form,
+// e.g. git@github.com:acme/api.git becomes github.com/acme/api
+func NormalizeRemote(url string) string {
+	url = strings.TrimSpace(url)
+	if scheme := strings.Index(url, "://"); scheme >= 0 {
+		url = url[scheme+3:]
+	} else if host, path, ok := strings.Cut(url, ":"); ok && !strings.Contains(host, "/") {
+		url = host + "/" + path
+	}
+	if at := strings.Index(url, "@"); at >= 0 && at < strings.Index(url+"/", "/") {
+		url = url[at+1:]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+}
+
+func expandHome(pattern string) string {
+	if !strings.HasPrefix(pattern, "~/") {
+		return pattern
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return pattern
+	}
+	return filepath.ToSlash(filepath.Join(home, pattern[2:]))
+}
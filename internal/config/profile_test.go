@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const profilesJSON = `{
+  "default_provider": "openai",
+  "profiles": {
+    "work": {
+      "default_provider": "openai",
+      "model": "gpt-4o",
+      "keys": "work",
+      "trailers": ["Refs: internal"],
+      "match_remote": ["github.com/acme/*"]
+    },
+    "personal": {
+      "default_provider": "groq",
+      "keys": "personal",
+      "match_path": ["~/oss/**"]
+    }
+  }
+}`
+
+func TestResolveProfileByRemote(t *testing.T) {
+	root := setupLayers(t, profilesJSON, "")
+
+	res, err := Resolve(ResolveOptions{RepoRoot: root, Remotes: []string{"git@github.com:acme/payments.git"}})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if res.Profile != "work" {
+		t.Fatalf("Profile = %q, want work", res.Profile)
+	}
+	if res.Model != "gpt-4o" || res.Keys != "work" {
+		t.Errorf("Resolve() = model %q keys %q, want gpt-4o and work", res.Model, res.Keys)
+	}
+	if got := res.Origin("profile").Source; got != SourceMatch {
+		t.Errorf("profile origin = %v, want match", got)
+	}
+	if got := res.Origin("model"); got.Source != SourceProfile || got.Detail != "work" {
+		t.Errorf("model origin = %v, want profile (work)", got)
+	}
+}
+
+func TestResolveProfileByPath(t *testing.T) {
+	setupLayers(t, profilesJSON, "")
+	root := filepath.Join(os.Getenv("HOME"), "oss", "vibecheck")
+
+	res, err := Resolve(ResolveOptions{RepoRoot: root})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if res.Profile != "personal" || res.DefaultProvider != "groq" {
+		t.Errorf("Resolve() = profile %q provider %q, want personal and groq", res.Profile, res.DefaultProvider)
+	}
+}
+
+func TestResolveProfileSelection(t *testing.T) {
+	root := setupLayers(t, profilesJSON, "")
+
+	t.Setenv("VIBECHECK_PROFILE", "personal")
+	res, err := Resolve(ResolveOptions{RepoRoot: root, Remotes: []string{"https://github.com/acme/payments"}})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if res.Profile != "personal" || res.DefaultProvider != "groq" {
+		t.Errorf("env selection = profile %q provider %q, want personal and groq", res.Profile, res.DefaultProvider)
+	}
+
+	res, err = Resolve(ResolveOptions{
+		RepoRoot: root,
+		Flags:    []Override{{Key: "profile", Flag: "profile", Value: "work"}},
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if res.Profile != "work" || res.Model != "gpt-4o" {
+		t.Errorf("flag selection = profile %q model %q, want work and gpt-4o", res.Profile, res.Model)
+	}
+}
+
+func TestResolveUnknownProfile(t *testing.T) {
+	root := setupLayers(t, profilesJSON, "")
+
+	_, err := Resolve(ResolveOptions{
+		RepoRoot: root,
+		Flags:    []Override{{Key: "profile", Flag: "profile", Value: "missing"}},
+	})
+	if err == nil {
+		t.Error("Resolve() with undefined profile should return error")
+	}
+}
+
+func TestNormalizeRemote(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"git@github.com:acme/payments.git", "github.com/acme/payments"},
+		{"https://github.com/acme/payments.git", "github.com/acme/payments"},
+		{"https://user@gitlab.com/acme/payments/", "gitlab.com/acme/payments"},
+		{"ssh://git@bitbucket.org/acme/payments.git", "bitbucket.org/acme/payments"},
+		{"/srv/git/payments.git", "/srv/git/payments"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRemote(tt.url); got != tt.want {
+			t.Errorf("NormalizeRemote(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoFileNames are the file names looked up at the repository root, in order
+var RepoFileNames = []string{".vibecheck.yaml", ".vibecheck.yml"}
+
+// RepoConfigPath returns the path of the repository config file under root.
+// The second return value is false when no such file exists.
+func RepoConfigPath(root string) (string, bool) {
+	for _, name := range RepoFileNames {
+		path := filepath.Join(root, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return filepath.Join(root, RepoFileNames[0]), false
+}
+
+// LoadRepo reads the repository config file under root.
+// A missing file yields an empty config rather than an error.
+func LoadRepo(root string) (*Config, error) {
+	path, exists := RepoConfigPath(root)
+	if !exists {
+		return &Config{}, nil
+	}
+	cfg, err := loadYAML(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckRepoLayer(cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// UserOnlyKeys can be set in the global file, the environment and with flags,
+// but not in a repository file that everyone who clones the repository runs
+// with: keys picks the file API keys are read from and written to, and the
+// redact keys would let a repository turn off the secret scan for its users,
+// allow by matching every value it finds
+var UserOnlyKeys = []string{"keys", "redact.disabled", "redact.allow", "redact.allow_paths"}
+
+// UserOnly reports whether key, or a profile's key such as profiles.work.keys,
+// is one of UserOnlyKeys
+func UserOnly(key string) bool {
+	if parts := strings.Split(key, "."); len(parts) == 3 && parts[0] == "profiles" {
+		key = parts[2]
+	}
+	return slices.Contains(UserOnlyKeys, key)
+}
+
+// CheckRepoLayer rejects the user-only settings of a repository file
+func CheckRepoLayer(cfg *Config) error {
+	for _, key := range UserOnlyKeys {
+		value, err := cfg.Get(key)
+		if err != nil {
+			return err
+		}
+		if v := reflect.ValueOf(value); !v.IsZero() && !(v.Kind() == reflect.Slice && v.Len() == 0) {
+			return fmt.Errorf("%s cannot be set in a repository config; set it with vibecheck config set %s", key, key)
+		}
+	}
+	for name, p := range cfg.Profiles {
+		if p != nil && p.Keys != "" {
+			return fmt.Errorf("profiles.%s.keys cannot be set in a repository config; define the profile globally", name)
+		}
+	}
+	return nil
+}
+
+// loadYAML strictly decodes a YAML config file, rejecting unknown keys
+func loadYAML(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cfg Config
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
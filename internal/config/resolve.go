@@ -0,0 +1,252 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/glob"
+)
+
+// Source identifies the layer an effective setting came from
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceGlobal  Source = "global"
+	SourceRepo    Source = "repo"
+	SourceProfile Source = "profile"
+	SourceMatch   Source = "match"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Origin explains where an effective setting came from
+type Origin struct {
+	Source Source
+	// Detail is the file path, environment variable or flag that set the value
+	Detail string
+}
+
+func (o Origin) String() string {
+	if o.Detail == "" {
+		return string(o.Source)
+	}
+	return fmt.Sprintf("%s (%s)", o.Source, o.Detail)
+}
+
+// Override is a single value supplied on the command line
+type Override struct {
+	Key   string
+	Flag  string
+	Value string
+}
+
+// ResolveOptions controls which layers take part in Resolve
+type ResolveOptions struct {
+	// RepoRoot is the working tree root; when empty the repository layer is skipped
+	RepoRoot string
+	// Remotes are the repository remote URLs used to pick a profile automatically
+	Remotes []string
+	// Flags are applied last and win over every other layer
+	Flags []Override
+}
+
+// Resolved is the effective configuration together with the origin of each key
+type Resolved struct {
+	*Config
+	Origins map[string]Origin
+	// RepoRoot is the working tree the configuration was resolved for, if any
+	RepoRoot string
+}
+
+// Origin returns where key was set; keys nobody set report the default layer
+func (r *Resolved) Origin(key string) Origin {
+	if o, ok := r.Origins[key]; ok {
+		return o
+	}
+	return Origin{Source: SourceDefault}
+}
+
+// EnvVar returns the environment variable that overrides a dotted key,
+// e.g. "model" becomes VIBECHECK_MODEL
+func EnvVar(key string) string {
+	r := strings.NewReplacer(".", "_", "-", "_")
+	return "VIBECHECK_" + strings.ToUpper(r.Replace(key))
+}
+
+// Resolve layers the built-in defaults, the global file, the repository file,
+// the active profile, VIBECHECK_* environment variables and command-line flags,
+// in that order
+func Resolve(opts ResolveOptions) (*Resolved, error) {
+	res := &Resolved{Config: Default(), Origins: map[string]Origin{}, RepoRoot: opts.RepoRoot}
+
+	globalPath, err := getConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(globalPath); err == nil {
+		global, err := Load()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", globalPath, err)
+		}
+		res.merge(global, Origin{Source: SourceGlobal, Detail: globalPath})
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if opts.RepoRoot != "" {
+		repo, err := LoadRepo(opts.RepoRoot)
+		if err != nil {
+			return nil, err
+		}
+		path, _ := RepoConfigPath(opts.RepoRoot)
+		res.merge(repo, Origin{Source: SourceRepo, Detail: path})
+	}
+
+	if err := res.applyProfile(opts); err != nil {
+		return nil, err
+	}
+
+	for _, f := range Fields(res.Config) {
+		name := EnvVar(f.Key)
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := res.Set(f.Key, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		res.Origins[f.Key] = Origin{Source: SourceEnv, Detail: name}
+	}
+
+	for _, o := range opts.Flags {
+		if err := res.Set(o.Key, o.Value); err != nil {
+			return nil, fmt.Errorf("--%s: %w", o.Flag, err)
+		}
+		res.Origins[o.Key] = Origin{Source: SourceFlag, Detail: "--" + o.Flag}
+	}
+
+	if err := res.checkGlobs(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkGlobs rejects path and remote globs that cannot be matched, naming the
+// layer that set them, so a bad pattern fails every command up front
+func (r *Resolved) checkGlobs() error {
+	check := func(key string, patterns []string) error {
+		for _, p := range patterns {
+			if err := glob.Validate(p); err != nil {
+				return fmt.Errorf("%s: %w, set in %s", key, err, r.Origin(key))
+			}
+		}
+		return nil
+	}
+	scopeGlobs := make([]string, 0, len(r.ScopeMap))
+	for pattern := range r.ScopeMap {
+		scopeGlobs = append(scopeGlobs, pattern)
+	}
+	sort.Strings(scopeGlobs)
+	if err := check("scope_map", scopeGlobs); err != nil {
+		return err
+	}
+	if err := check("redact.allow_paths", r.Redact.AllowPaths); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(r.Profiles))
+	for name := range r.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := r.Profiles[name]
+		if p == nil {
+			continue
+		}
+		if err := check("profiles."+name+".match_remote", p.MatchRemote); err != nil {
+			return err
+		}
+		if err := check("profiles."+name+".match_path", p.MatchPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyProfile merges the selected profile. The name comes from the profile
+// flag, VIBECHECK_PROFILE or the profile key, otherwise the first profile whose
+// globs match the repository is used.
+func (r *Resolved) applyProfile(opts ResolveOptions) error {
+	name := r.Profile
+	if env := os.Getenv(EnvVar("profile")); env != "" {
+		name = env
+	}
+	for _, o := range opts.Flags {
+		if o.Key == "profile" {
+			name = o.Value
+		}
+	}
+
+	if name == "" {
+		matched, pattern := matchProfile(r.Profiles, opts.RepoRoot, opts.Remotes)
+		if matched == "" {
+			return nil
+		}
+		name = matched
+		r.Profile = matched
+		r.Origins["profile"] = Origin{Source: SourceMatch, Detail: pattern}
+	}
+
+	p, ok := r.Profiles[name]
+	if !ok || p == nil {
+		return fmt.Errorf("%w: profile %q is not defined", ErrUnknownKey, name)
+	}
+	r.merge(p.layer(), Origin{Source: SourceProfile, Detail: name})
+	return nil
+}
+
+// merge copies every non-zero value of layer over the current config
+func (r *Resolved) merge(layer *Config, origin Origin) {
+	mergeValue(reflect.ValueOf(r.Config).Elem(), reflect.ValueOf(layer).Elem(), "", origin, r.Origins)
+}
+
+func mergeValue(dst, src reflect.Value, prefix string, origin Origin, origins map[string]Origin) {
+	switch {
+	case dst.Kind() == reflect.Struct:
+		t := dst.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := fieldName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			mergeValue(dst.Field(i), src.Field(i), joinKey(prefix, name), origin, origins)
+		}
+	case isSection(dst.Type()):
+		for _, k := range src.MapKeys() {
+			entry := src.MapIndex(k)
+			if entry.IsNil() {
+				continue
+			}
+			if dst.IsNil() {
+				dst.Set(reflect.MakeMap(dst.Type()))
+			}
+			target := dst.MapIndex(k)
+			if !target.IsValid() || target.IsNil() {
+				target = reflect.New(dst.Type().Elem().Elem())
+				dst.SetMapIndex(k, target)
+			}
+			mergeValue(target.Elem(), entry.Elem(), joinKey(prefix, k.String()), origin, origins)
+		}
+	default:
+		if src.IsZero() {
+			return
+		}
+		dst.Set(src)
+		origins[prefix] = origin
+	}
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupLayers(t *testing.T, global, repo string) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if global != "" {
+		if err := os.WriteFile(filepath.Join(home, ".vibecheck.json"), []byte(global), 0644); err != nil {
+			t.Fatalf("Failed to write global config: %v", err)
+		}
+	}
+
+	root := t.TempDir()
+	if repo != "" {
+		if err := os.WriteFile(filepath.Join(root, ".vibecheck.yaml"), []byte(repo), 0644); err != nil {
+			t.Fatalf("Failed to write repo config: %v", err)
+		}
+	}
+	return root
+}
+
+func TestResolveLayering(t *testing.T) {
+	root := setupLayers(t,
+		`{"default_provider": "gemini", "model": "gemini-2.0", "style": "terse"}`,
+		"default_provider: groq\nscopes:\n  - cli\n  - llm\n",
+	)
+	t.Setenv("VIBECHECK_STYLE", "verbose")
+
+	res, err := Resolve(ResolveOptions{
+		RepoRoot: root,
+		Flags:    []Override{{Key: "model", Flag: "model", Value: "llama-3.1-8b"}},
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		want   string
+		source Source
+	}{
+		{"default_provider", "groq", SourceRepo},
+		{"model", "llama-3.1-8b", SourceFlag},
+		{"style", "verbose", SourceEnv},
+		{"scopes", "cli,llm", SourceRepo},
+		{"language", "", SourceDefault},
+	}
+	for _, tt := range tests {
+		value, err := res.Get(tt.key)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", tt.key, err)
+		}
+		if got := FormatValue(value); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+		}
+		if got := res.Origin(tt.key).Source; got != tt.source {
+			t.Errorf("%s origin = %v, want %v", tt.key, got, tt.source)
+		}
+	}
+}
+
+func TestResolveDefaults(t *testing.T) {
+	setupLayers(t, "", "")
+
+	res, err := Resolve(ResolveOptions{})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if res.DefaultProvider != "openai" {
+		t.Errorf("DefaultProvider = %q, want openai", res.DefaultProvider)
+	}
+	if res.Origin("default_provider").Source != SourceDefault {
+		t.Errorf("default_provider origin = %v, want default", res.Origin("default_provider"))
+	}
+}
+
+func TestResolveInvalidFlag(t *testing.T) {
+	setupLayers(t, "", "")
+
+	_, err := Resolve(ResolveOptions{Flags: []Override{{Key: "nope", Flag: "nope", Value: "x"}}})
+	if err == nil {
+		t.Error("Resolve() with unknown key should return error")
+	}
+}
+
+func TestResolveInvalidGlob(t *testing.T) {
+	tests := []struct {
+		repo string
+		key  string
+	}{
+		{"scope_map:\n  \"[z-a]/**\": web\n", "scope_map"},
+		{"profiles:\n  work:\n    match_remote:\n      - \"[[:nope:]]\"\n", "profiles.work.match_remote"},
+	}
+	for _, tt := range tests {
+		root := setupLayers(t, "", tt.repo)
+		_, err := Resolve(ResolveOptions{RepoRoot: root})
+		if err == nil || !strings.HasPrefix(err.Error(), tt.key+": ") || !strings.Contains(err.Error(), "set in repo") {
+			t.Errorf("Resolve(%q) error = %v", tt.repo, err)
+		}
+	}
+
+	root := setupLayers(t, "", "scope_map:\n  \"[]abc]/**\": web\n")
+	if _, err := Resolve(ResolveOptions{RepoRoot: root}); err != nil {
+		t.Errorf("Resolve() with a literal ] in a class error = %v", err)
+	}
+}
+
+func TestResolveUserOnlyKeys(t *testing.T) {
+	for _, repo := range []string{
+		"keys: /../../tmp/x\n",
+		"profiles:\n  work:\n    keys: work\n",
+		"redact:\n  disabled: true\n",
+		"redact:\n  allow_paths: [\"**\"]\n",
+		"redact:\n  allow: [\".*\"]\n",
+	} {
+		root := setupLayers(t, "", repo)
+		if _, err := Resolve(ResolveOptions{RepoRoot: root}); err == nil || !strings.Contains(err.Error(), "cannot be set in a repository config") {
+			t.Errorf("Resolve(%q) error = %v", repo, err)
+		}
+	}
+
+	root := setupLayers(t, `{"keys": "work"}`, "model: sonar\n")
+	res, err := Resolve(ResolveOptions{RepoRoot: root})
+	if err != nil || res.Keys != "work" {
+		t.Errorf("Resolve() with global keys = %v, %v", res, err)
+	}
+
+	root = setupLayers(t, `{"redact": {"allow_paths": ["testdata/**"]}}`, "redact:\n  block: true\n")
+	res, err = Resolve(ResolveOptions{RepoRoot: root})
+	if err != nil || !res.Redact.Block || len(res.Redact.AllowPaths) != 1 {
+		t.Errorf("Resolve() with global allow_paths = %+v, %v", res, err)
+	}
+
+	allowAll := &Config{Redact: RedactConfig{Allow: []string{".*"}}}
+	if err := CheckRepoLayer(allowAll); err == nil || !strings.Contains(err.Error(), "redact.allow cannot be set") {
+		t.Errorf("CheckRepoLayer(redact.allow) error = %v", err)
+	}
+
+	if !UserOnly("keys") || !UserOnly("profiles.work.keys") || !UserOnly("redact.disabled") || !UserOnly("redact.allow") || UserOnly("model") {
+		t.Error("UserOnly() misclassified a key")
+	}
+}
+
+func TestLoadRepo(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		cfg, err := LoadRepo(t.TempDir())
+		if err != nil {
+			t.Fatalf("LoadRepo() error = %v", err)
+		}
+		if cfg.DefaultProvider != "" {
+			t.Errorf("LoadRepo() DefaultProvider = %q, want empty", cfg.DefaultProvider)
+		}
+	})
+
+	t.Run("yml extension", func(t *testing.T) {
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, ".vibecheck.yml"), []byte("model: sonar\n"), 0644); err != nil {
+			t.Fatalf("Failed to write repo config: %v", err)
+		}
+		cfg, err := LoadRepo(root)
+		if err != nil {
+			t.Fatalf("LoadRepo() error = %v", err)
+		}
+		if cfg.Model != "sonar" {
+			t.Errorf("LoadRepo() Model = %q, want sonar", cfg.Model)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, ".vibecheck.yaml"), []byte("modle: sonar\n"), 0644); err != nil {
+			t.Fatalf("Failed to write repo config: %v", err)
+		}
+		if _, err := LoadRepo(root); err == nil {
+			t.Error("LoadRepo() with unknown key should return error")
+		}
+	})
+}
+
+func TestEnvVar(t *testing.T) {
+	if got := EnvVar("default_provider"); got != "VIBECHECK_DEFAULT_PROVIDER" {
+		t.Errorf("EnvVar() = %q, want VIBECHECK_DEFAULT_PROVIDER", got)
+	}
+}
+
+func TestResolveZeroOverride(t *testing.T) {
+	root := setupLayers(t, "", "diff:\n  context: 10\n")
+
+	res, err := Resolve(ResolveOptions{
+		RepoRoot: root,
+		Flags:    []Override{{Key: "diff.context", Flag: "diff-context", Value: "0"}},
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if res.Diff.Context == nil || *res.Diff.Context != 0 {
+		t.Errorf("diff.context = %v, want 0", res.Diff.Context)
+	}
+	if got := res.Origin("diff.context").Source; got != SourceFlag {
+		t.Errorf("diff.context origin = %v, want %v", got, SourceFlag)
+	}
+}
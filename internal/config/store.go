@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GlobalPath returns the path of the global config file
+func GlobalPath() (string, error) {
+	return getConfigPath()
+}
+
+// SetValue validates raw against the type of key and writes it into the config
+// file at path. Other settings already in the file are left untouched.
+func SetValue(path, key, raw string) error {
+	scratch := &Config{}
+	if err := scratch.Set(key, raw); err != nil {
+		return err
+	}
+	value, err := scratch.Get(key)
+	if err != nil {
+		return err
+	}
+
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+	setPath(doc, strings.Split(key, "."), value)
+	return writeDocument(path, doc)
+}
+
+// UnsetValue removes key from the config file at path
+func UnsetValue(path, key string) error {
+	if _, err := KindOf(key); err != nil {
+		return err
+	}
+
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+	if !unsetPath(doc, strings.Split(key, ".")) {
+		return nil
+	}
+	return writeDocument(path, doc)
+}
+
+// LoadFile reads a single config file, picking the format from its extension
+func LoadFile(path string) (*Config, error) {
+	if isYAML(path) {
+		return loadYAML(path)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// readDocument loads the raw key/value tree of a config file so writes can be
+// merged without dropping keys this version does not know about
+func readDocument(path string) (map[string]any, error) {
+	doc := map[string]any{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isYAML(path) {
+		err = yaml.Unmarshal(data, &doc)
+	} else if len(strings.TrimSpace(string(data))) > 0 {
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+func writeDocument(path string, doc map[string]any) error {
+	var (
+		data []byte
+		err  error
+	)
+	if isYAML(path) {
+		data, err = yaml.Marshal(doc)
+	} else {
+		data, err = json.MarshalIndent(doc, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func isYAML(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func setPath(doc map[string]any, parts []string, value any) {
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := doc[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			doc[part] = next
+		}
+		doc = next
+	}
+	doc[parts[len(parts)-1]] = value
+}
+
+func unsetPath(doc map[string]any, parts []string) bool {
+	if len(parts) == 1 {
+		if _, ok := doc[parts[0]]; !ok {
+			return false
+		}
+		delete(doc, parts[0])
+		return true
+	}
+	next, ok := doc[parts[0]].(map[string]any)
+	if !ok {
+		return false
+	}
+	removed := unsetPath(next, parts[1:])
+	if removed && len(next) == 0 {
+		delete(doc, parts[0])
+	}
+	return removed
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetValueMergesIntoExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vibecheck.json")
+	existing := `{"default_provider": "gemini", "managed_by": "chezmoi"}`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := SetValue(path, "scopes", "cli,llm"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("written config is not JSON: %v", err)
+	}
+	if doc["default_provider"] != "gemini" {
+		t.Errorf("default_provider = %v, want gemini", doc["default_provider"])
+	}
+	if doc["managed_by"] != "chezmoi" {
+		t.Errorf("unknown key was dropped: %v", doc)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if strings.Join(cfg.Scopes, ",") != "cli,llm" {
+		t.Errorf("Scopes = %v, want [cli llm]", cfg.Scopes)
+	}
+}
+
+func TestSetValueValidatesType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vibecheck.json")
+	if err := SetValue(path, "no_such_key", "x"); err == nil {
+		t.Error("SetValue() with unknown key should return error")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("SetValue() should not create a file when validation fails")
+	}
+}
+
+func TestSetValueYAML(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, ".vibecheck.yaml")
+
+	if err := SetValue(path, "model", "sonar"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	cfg, err := LoadRepo(root)
+	if err != nil {
+		t.Fatalf("LoadRepo() error = %v", err)
+	}
+	if cfg.Model != "sonar" {
+		t.Errorf("Model = %q, want sonar", cfg.Model)
+	}
+}
+
+func TestUnsetValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vibecheck.json")
+	if err := SetValue(path, "model", "gpt-4o"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	if err := UnsetValue(path, "model"); err != nil {
+		t.Fatalf("UnsetValue() error = %v", err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if cfg.Model != "" {
+		t.Errorf("Model = %q, want empty after unset", cfg.Model)
+	}
+}
+
+func TestSetDefaultProviderKeepsOtherSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := Save(&Config{DefaultProvider: "openai", Model: "gpt-4o"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := SetDefaultProvider("groq"); err != nil {
+		t.Fatalf("SetDefaultProvider() error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.DefaultProvider != "groq" || cfg.Model != "gpt-4o" {
+		t.Errorf("Load() = %+v, want provider groq and model gpt-4o", cfg)
+	}
+}
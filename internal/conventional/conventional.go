@@ -0,0 +1,93 @@
+// Package conventional parses and rewrites Conventional Commits headers
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Header is the first line of a Conventional Commit: type(scope)!: description
+type Header struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\r\n]*)\))?(!)?: *(.*)$`)
+
+// ParseHeader parses a single header line. It reports false when the line is
+// not a Conventional Commit header.
+func ParseHeader(line string) (Header, bool) {
+	m := headerPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Header{}, false
+	}
+	return Header{
+		Type:        strings.ToLower(m[1]),
+		Scope:       strings.TrimSpace(m[2]),
+		Breaking:    m[3] == "!",
+		Description: strings.TrimSpace(m[4]),
+	}, true
+}
+
+func (h Header) String() string {
+	var b strings.Builder
+	b.WriteString(h.Type)
+	if h.Scope != "" {
+		b.WriteString("(" + h.Scope + ")")
+	}
+	if h.Breaking {
+		b.WriteString("!")
+	}
+	b.WriteString(": ")
+	b.WriteString(h.Description)
+	return b.String()
+}
+
+// SplitMessage separates the header line of a commit message from the rest.
+// Leading blank lines are skipped.
+func SplitMessage(message string) (string, string) {
+	message = strings.TrimLeft(message, "\r\n")
+	header, rest, _ := strings.Cut(message, "\n")
+	return strings.TrimRight(header, "\r"), rest
+}
+
+// RewriteHeader applies fn to the parsed header of message and returns the
+// message with the new header. Messages without a valid header are returned as-is.
+func RewriteHeader(message string, fn func(*Header)) string {
+	line, rest := SplitMessage(message)
+	h, ok := ParseHeader(line)
+	if !ok {
+		return message
+	}
+	fn(&h)
+	if rest == "" {
+		return h.String()
+	}
+	return h.String() + "\n" + rest
+}
+
+// BreakingFooter is the footer token that describes a breaking change
+const BreakingFooter = "BREAKING CHANGE"
+
+// MarkBreaking adds "!" to the header and appends a BREAKING CHANGE footer with
+// description, unless the message already has one
+func MarkBreaking(message, description string) string {
+	message = RewriteHeader(message, func(h *Header) { h.Breaking = true })
+	if HasBreakingFooter(message) {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + BreakingFooter + ": " + description
+}
+
+// HasBreakingFooter reports whether message carries a BREAKING CHANGE (or
+// BREAKING-CHANGE) footer
+func HasBreakingFooter(message string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, BreakingFooter+":") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package conventional
+
+import "testing"
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		line string
+		want Header
+		ok   bool
+	}{
+		{"feat(llm): add provider", Header{Type: "feat", Scope: "llm", Description: "add provider"}, true},
+		{"fix: resolve panic", Header{Type: "fix", Description: "resolve panic"}, true},
+		{"refactor(api)!: drop v1 routes", Header{Type: "refactor", Scope: "api", Breaking: true, Description: "drop v1 routes"}, true},
+		{"Feat!: shout", Header{Type: "feat", Breaking: true, Description: "shout"}, true},
+		{"Update README", Header{}, false},
+		{"", Header{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseHeader(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseHeader(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHeaderString(t *testing.T) {
+	h := Header{Type: "feat", Scope: "cli", Breaking: true, Description: "remove flag"}
+	if got := h.String(); got != "feat(cli)!: remove flag" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestRewriteHeader(t *testing.T) {
+	msg := "\nfeat(commands): add thing\n- detail one\n"
+	got := RewriteHeader(msg, func(h *Header) { h.Scope = "cmd" })
+	want := "feat(cmd): add thing\n- detail one\n"
+	if got != want {
+		t.Errorf("RewriteHeader() = %q, want %q", got, want)
+	}
+
+	plain := "not conventional"
+	if got := RewriteHeader(plain, func(h *Header) { h.Scope = "x" }); got != plain {
+		t.Errorf("RewriteHeader() changed a non-conventional message: %q", got)
+	}
+}
+
+func TestMarkBreaking(t *testing.T) {
+	got := MarkBreaking("refactor(llm): drop Register\n\n- use RegisterProvider", "removes or renames Register")
+	want := "refactor(llm)!: drop Register\n\n- use RegisterProvider\n\nBREAKING CHANGE: removes or renames Register"
+	if got != want {
+		t.Errorf("MarkBreaking() = %q, want %q", got, want)
+	}
+
+	// An existing footer written by the model is kept
+	msg := "feat: new api\n\nBREAKING CHANGE: callers must migrate"
+	if got := MarkBreaking(msg, "ignored"); got != "feat!: new api\n\nBREAKING CHANGE: callers must migrate" {
+		t.Errorf("MarkBreaking() with footer = %q", got)
+	}
+}
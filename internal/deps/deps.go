@@ -0,0 +1,184 @@
+// Package deps reads dependency manifests such as go.mod and package.json and
+// reports exactly which dependencies a change added, removed or bumped
+package deps
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Change is one dependency that differs between two versions of a manifest.
+// From is empty for additions and To is empty for removals.
+type Change struct {
+	Manifest string
+	Name     string
+	From     string
+	To       string
+}
+
+func (c Change) String() string {
+	switch {
+	case c.From == "":
+		return fmt.Sprintf("add %s %s", c.Name, c.To)
+	case c.To == "":
+		return fmt.Sprintf("remove %s %s", c.Name, c.From)
+	default:
+		return fmt.Sprintf("bump %s %s -> %s", c.Name, c.From, c.To)
+	}
+}
+
+// parser reads the dependencies of a manifest into name -> version
+type parser func(content string) (map[string]string, error)
+
+var parsers = map[string]parser{
+	"go.mod":        parseGoMod,
+	"package.json":  parsePackageJSON,
+	"composer.json": parseComposerJSON,
+	"Cargo.toml":    parseCargoToml,
+}
+
+// lockfiles change together with manifests and carry no facts of their own
+var lockfiles = map[string]bool{
+	"go.sum":              true,
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"Cargo.lock":          true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+}
+
+func parserFor(file string) parser {
+	base := path.Base(file)
+	if p, ok := parsers[base]; ok {
+		return p
+	}
+	if strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt") {
+		return parseRequirements
+	}
+	return nil
+}
+
+// IsManifest reports whether file is a manifest vibecheck can read
+func IsManifest(file string) bool {
+	return parserFor(file) != nil
+}
+
+// IsLockfile reports whether file is a lockfile that accompanies a manifest
+func IsLockfile(file string) bool {
+	return lockfiles[path.Base(file)]
+}
+
+// Diff compares two versions of the manifest at file. Either side may be empty
+// when the manifest is added or deleted.
+func Diff(file, before, after string) ([]Change, error) {
+	parse := parserFor(file)
+	if parse == nil {
+		return nil, fmt.Errorf("%s is not a supported manifest", file)
+	}
+	old, err := parse(before)
+	if err != nil {
+		return nil, fmt.Errorf("parse old %s: %w", file, err)
+	}
+	cur, err := parse(after)
+	if err != nil {
+		return nil, fmt.Errorf("parse new %s: %w", file, err)
+	}
+
+	names := map[string]bool{}
+	for n := range old {
+		names[n] = true
+	}
+	for n := range cur {
+		names[n] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+	sort.Strings(sorted)
+
+	var changes []Change
+	for _, n := range sorted {
+		if old[n] != cur[n] {
+			changes = append(changes, Change{Manifest: file, Name: n, From: old[n], To: cur[n]})
+		}
+	}
+	return changes, nil
+}
+
+// Explains reports whether every added or removed line of a manifest's diff
+// section mentions one of the changed dependencies, or only opens or closes a
+// dependency section.
+// When it does, the manifest changed for no other reason.
+func Explains(changes []Change, section string) bool {
+	inHunk := false
+	for _, line := range strings.Split(section, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			inHunk = true
+			continue
+		}
+		if !inHunk || line == "" || (line[0] != '+' && line[0] != '-') {
+			continue
+		}
+		body := strings.TrimSpace(line[1:])
+		if !structural(body) && !mentionsAny(body, changes) {
+			return false
+		}
+	}
+	return true
+}
+
+// structural reports lines that only open or close a dependency section
+func structural(line string) bool {
+	if strings.Trim(line, "{}[](),") == "" || line == "require (" {
+		return true
+	}
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "dependencies]") {
+		return true
+	}
+	key, rest, ok := strings.Cut(line, ":")
+	if !ok || strings.TrimSpace(rest) != "{" {
+		return false
+	}
+	switch strings.Trim(key, `" `) {
+	case "dependencies", "devDependencies", "peerDependencies", "optionalDependencies", "require", "require-dev":
+		return true
+	}
+	return false
+}
+
+func mentionsAny(line string, changes []Change) bool {
+	for _, c := range changes {
+		if strings.Contains(strings.ToLower(line), strings.ToLower(c.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Message writes a Conventional Commit for a dependency-only change
+func Message(changes []Change) string {
+	if len(changes) == 1 {
+		c := changes[0]
+		switch {
+		case c.From == "":
+			return fmt.Sprintf("chore(deps): add %s %s", c.Name, c.To)
+		case c.To == "":
+			return fmt.Sprintf("chore(deps): remove %s", c.Name)
+		default:
+			return fmt.Sprintf("chore(deps): bump %s from %s to %s", c.Name, c.From, c.To)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "chore(deps): update %d dependencies\n", len(changes))
+	for _, c := range changes {
+		b.WriteString("\n- " + c.String())
+	}
+	return b.String()
+}
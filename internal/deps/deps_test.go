@@ -0,0 +1,157 @@
+package deps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffGoMod(t *testing.T) {
+	before := `module example.com/app
+
+go 1.24
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/stretchr/testify v1.9.0
+	golang.org/x/term v0.38.0 // indirect
+)
+`
+	after := `module example.com/app
+
+go 1.24
+
+require github.com/spf13/cobra v1.9.1
+
+require (
+	github.com/stretchr/testify v1.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+`
+	got, err := Diff("go.mod", before, after)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	want := []Change{
+		{Manifest: "go.mod", Name: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.9.1"},
+		{Manifest: "go.mod", Name: "golang.org/x/term", From: "v0.38.0"},
+		{Manifest: "go.mod", Name: "gopkg.in/yaml.v3", To: "v3.0.1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+	if got[0].String() != "bump github.com/spf13/cobra v1.8.0 -> v1.9.1" {
+		t.Errorf("String() = %q", got[0].String())
+	}
+}
+
+func TestDiffPackageJSON(t *testing.T) {
+	before := `{"name": "app", "version": "1.0.0", "dependencies": {"react": "^18.2.0"}}`
+	after := `{"name": "app", "version": "1.0.1", "dependencies": {"react": "^18.3.1"}, "devDependencies": {"vitest": "^1.0.0"}}`
+	got, err := Diff("web/package.json", before, after)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	want := []Change{
+		{Manifest: "web/package.json", Name: "react", From: "^18.2.0", To: "^18.3.1"},
+		{Manifest: "web/package.json", Name: "vitest", To: "^1.0.0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+
+	if _, err := Diff("package.json", "{", ""); err == nil {
+		t.Error("Diff() accepted invalid JSON")
+	}
+}
+
+func TestDiffRequirements(t *testing.T) {
+	before := "# pinned\nDjango==4.2.0\nrequests>=2.0 ; python_version > '3.8'\n-r base.txt\n"
+	after := "Django==5.0.1\nrequests>=2.31\nnumpy\n"
+	got, err := Diff("requirements-dev.txt", before, after)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	want := []Change{
+		{Manifest: "requirements-dev.txt", Name: "django", From: "4.2.0", To: "5.0.1"},
+		{Manifest: "requirements-dev.txt", Name: "numpy", To: "*"},
+		{Manifest: "requirements-dev.txt", Name: "requests", From: ">=2.0", To: ">=2.31"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffCargoToml(t *testing.T) {
+	before := `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0.190"
+tokio = { version = "1.33", features = ["full"] }
+`
+	after := `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0.193"
+tokio = { version = "1.35", features = ["full"] }
+
+[target.'cfg(unix)'.dependencies]
+nix = "0.27"
+`
+	got, err := Diff("Cargo.toml", before, after)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	want := []Change{
+		{Manifest: "Cargo.toml", Name: "nix", To: "0.27"},
+		{Manifest: "Cargo.toml", Name: "serde", From: "1.0.190", To: "1.0.193"},
+		{Manifest: "Cargo.toml", Name: "tokio", From: "1.33", To: "1.35"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExplains(t *testing.T) {
+	changes := []Change{{Name: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.9.1"}}
+	section := "diff --git a/go.mod b/go.mod\n--- a/go.mod\n+++ b/go.mod\n@@ -1,3 +1,3 @@\n module x\n-require github.com/spf13/cobra v1.8.0\n+require github.com/spf13/cobra v1.9.1\n"
+	if !Explains(changes, section) {
+		t.Error("Explains() = false for a pure bump")
+	}
+
+	section += "@@ -5 +5 @@\n-go 1.22\n+go 1.24\n"
+	if Explains(changes, section) {
+		t.Error("Explains() = true although the go directive changed")
+	}
+
+	pkg := "@@ -1,2 +1,5 @@\n {\n+  \"dependencies\": {\n+    \"react\": \"^18.3.1\"\n+  }\n }\n"
+	if !Explains([]Change{{Name: "react", To: "^18.3.1"}}, pkg) {
+		t.Error("Explains() = false for a new dependencies section")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	one := []Change{{Name: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.9.1"}}
+	if got := Message(one); got != "chore(deps): bump github.com/spf13/cobra from v1.8.0 to v1.9.1" {
+		t.Errorf("Message() = %q", got)
+	}
+
+	many := []Change{{Name: "react", From: "18.2.0", To: "18.3.1"}, {Name: "left-pad", From: "1.3.0"}}
+	want := "chore(deps): update 2 dependencies\n\n- bump react 18.2.0 -> 18.3.1\n- remove left-pad 1.3.0"
+	if got := Message(many); got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+}
+
+func TestFileKinds(t *testing.T) {
+	if !IsManifest("svc/go.mod") || !IsManifest("requirements.txt") || IsManifest("main.go") {
+		t.Error("IsManifest() misclassified a file")
+	}
+	if !IsLockfile("web/yarn.lock") || IsLockfile("go.mod") {
+		t.Error("IsLockfile() misclassified a file")
+	}
+}
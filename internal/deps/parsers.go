@@ -0,0 +1,151 @@
+package deps
+
+import (
+	"bufio"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// parseGoMod reads require directives, both single line and blocks
+func parseGoMod(content string) (map[string]string, error) {
+	out := map[string]string{}
+	inBlock := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+			continue
+		case fields[0] == "require" && len(fields) == 3:
+			out[fields[1]] = fields[2]
+		case inBlock && len(fields) == 2:
+			out[fields[0]] = fields[1]
+		}
+	}
+	return out, scanner.Err()
+}
+
+// parsePackageJSON reads the npm dependency sections
+func parsePackageJSON(content string) (map[string]string, error) {
+	return parseJSONSections(content, "dependencies", "devDependencies", "peerDependencies", "optionalDependencies")
+}
+
+// parseComposerJSON reads the composer require sections
+func parseComposerJSON(content string) (map[string]string, error) {
+	return parseJSONSections(content, "require", "require-dev")
+}
+
+func parseJSONSections(content string, sections ...string) (map[string]string, error) {
+	out := map[string]string{}
+	if strings.TrimSpace(content) == "" {
+		return out, nil
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, err
+	}
+	for _, s := range sections {
+		raw, ok := doc[s]
+		if !ok {
+			continue
+		}
+		var deps map[string]string
+		if err := json.Unmarshal(raw, &deps); err != nil {
+			return nil, err
+		}
+		for name, version := range deps {
+			out[name] = version
+		}
+	}
+	return out, nil
+}
+
+// requirement matches "name[extras] <specifier>" in a requirements file
+var requirement = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*(.*)$`)
+
+// parseRequirements reads pip requirements files. Options such as -r and -e
+// and environment markers are ignored; "==" pins are reported as bare versions
+// and unpinned requirements as "*".
+func parseRequirements(content string) (map[string]string, error) {
+	out := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		m := requirement.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := strings.ToLower(strings.ReplaceAll(m[1], "_", "-"))
+		spec := strings.ReplaceAll(m[2], " ", "")
+		if spec == "" {
+			spec = "*"
+		}
+		out[name] = strings.TrimPrefix(spec, "==")
+	}
+	return out, scanner.Err()
+}
+
+var (
+	tomlSection = regexp.MustCompile(`^\[([^\]]+)\]$`)
+	tomlEntry   = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*=\s*(.+)$`)
+	tomlVersion = regexp.MustCompile(`version\s*=\s*"([^"]*)"`)
+)
+
+// parseCargoToml reads the dependency tables of a Cargo manifest, including
+// target specific ones. Inline tables report their version key; path and git
+// dependencies without one are reported by their source.
+func parseCargoToml(content string) (map[string]string, error) {
+	out := map[string]string{}
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := tomlSection.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			continue
+		}
+		if !strings.HasSuffix(section, "dependencies") {
+			continue
+		}
+		m := tomlEntry.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := strings.TrimSpace(m[2])
+		switch {
+		case strings.HasPrefix(value, `"`):
+			out[m[1]] = strings.Trim(value, `"`)
+		case strings.HasPrefix(value, "{"):
+			if v := tomlVersion.FindStringSubmatch(value); v != nil {
+				out[m[1]] = v[1]
+			} else {
+				out[m[1]] = strings.Join(strings.Fields(value), " ")
+			}
+		}
+	}
+	return out, scanner.Err()
+}
@@ -0,0 +1,186 @@
+// Package fixup finds the commit a staged change most likely fixes, from the
+// history of the lines the change touches
+package fixup
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/patch"
+)
+
+// SystemPrompt replaces the commit message instructions of the providers
+const SystemPrompt = `You are a senior software engineer deciding which earlier commit on a branch a staged change fixes.
+Given the candidate commits, with how many of the changed lines each one last touched, and the staged diff, pick the single commit the change belongs to when the branch is cleaned up with git rebase --autosquash.
+Prefer the commit whose intent the change completes or corrects, not merely the one that touched the most lines.
+
+Respond with the short hash of that commit only, without commentary.
+
+The user's extra context is in the next message and the candidates and the staged diff in the one after.`
+
+// hunkHeader matches the old range of a hunk: "@@ -start[,count] +..."
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+`)
+
+// Lines returns the lines of the pre-image that h changes: the deleted lines,
+// and for lines added without deleting any, the lines on either side of them.
+// Hunks of new files have no pre-image and return none.
+func Lines(h patch.Hunk) []int {
+	text := h.Text
+	if text == "" {
+		_, body, ok := strings.Cut(h.Header, "\n@@")
+		if !ok {
+			return nil
+		}
+		text = "@@" + body
+	}
+
+	var lines []int
+	old, last := 0, 0
+	// insertion is the old line an unpaired run of added lines goes before
+	insertion, paired := 0, false
+	flush := func() {
+		if insertion == 0 {
+			return
+		}
+		if !paired {
+			if insertion > 1 {
+				lines = append(lines, insertion-1)
+			}
+			if insertion <= last {
+				lines = append(lines, insertion)
+			}
+		}
+		insertion, paired = 0, false
+	}
+	deleted := false
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				old, last = 0, 0
+				continue
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			old, last, deleted = start, start+count-1, false
+			if count == 0 {
+				// Pure insertions name the line they follow
+				old, last = start+1, start
+			}
+		case old == 0:
+		case strings.HasPrefix(line, "-"):
+			if insertion != 0 {
+				paired = true
+			}
+			lines = append(lines, old)
+			old++
+			deleted = true
+		case strings.HasPrefix(line, "+"):
+			if insertion == 0 {
+				insertion, paired = old, deleted
+			}
+		case strings.HasPrefix(line, " "):
+			flush()
+			old++
+			deleted = false
+		}
+	}
+	flush()
+
+	sort.Ints(lines)
+	unique := lines[:0]
+	for i, n := range lines {
+		if i == 0 || n != lines[i-1] {
+			unique = append(unique, n)
+		}
+	}
+	return unique
+}
+
+// Candidate is a commit that last changed some of the lines a staged change
+// touches
+type Candidate struct {
+	Hash    string
+	Subject string
+	// Lines counts the touched lines the commit last changed
+	Lines int
+}
+
+// Rank orders the commits of votes, which count the touched lines each last
+// changed, by that count. Ties go to the commit that comes first in order,
+// typically the newest.
+func Rank(votes map[string]int, order []string) []Candidate {
+	position := make(map[string]int, len(order))
+	for i, hash := range order {
+		position[hash] = i
+	}
+	candidates := make([]Candidate, 0, len(votes))
+	for hash, n := range votes {
+		if n > 0 {
+			candidates = append(candidates, Candidate{Hash: hash, Lines: n})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return position[a.Hash] < position[b.Hash]
+	})
+	return candidates
+}
+
+// Ambiguous reports whether blame alone does not settle the target: the
+// runner-up last changed at least half as many lines as the leader
+func Ambiguous(candidates []Candidate) bool {
+	return len(candidates) > 1 && 2*candidates[1].Lines >= candidates[0].Lines
+}
+
+// Describe lists the candidates for the provider
+func Describe(candidates []Candidate) string {
+	var b strings.Builder
+	for _, c := range candidates {
+		fmt.Fprintf(&b, "- %s %s (%s)\n", short(c.Hash), c.Subject, lineCount(c.Lines))
+	}
+	return b.String()
+}
+
+// Choose returns the index of the candidate a provider response names by
+// hash, or -1 if it names none
+func Choose(response string, candidates []Candidate) int {
+	for _, word := range strings.FieldsFunc(strings.ToLower(response), func(r rune) bool {
+		return !('0' <= r && r <= '9' || 'a' <= r && r <= 'f')
+	}) {
+		if len(word) < 4 {
+			continue
+		}
+		for i, c := range candidates {
+			if strings.HasPrefix(c.Hash, word) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func short(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func lineCount(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
@@ -0,0 +1,98 @@
+package fixup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/patch"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		hunk patch.Hunk
+		want []int
+	}{
+		{
+			name: "replacement",
+			hunk: patch.Hunk{Text: "@@ -10,4 +10,4 @@ func f()\n a\n-b\n+B\n c\n-d\n+D\n"},
+			want: []int{11, 13},
+		},
+		{
+			name: "insertion between lines",
+			hunk: patch.Hunk{Text: "@@ -1,4 +1,5 @@\n a\n b\n+x\n c\n d\n"},
+			want: []int{2, 3},
+		},
+		{
+			name: "insertion without context",
+			hunk: patch.Hunk{Text: "@@ -5,0 +6,2 @@\n+x\n+y\n"},
+			want: []int{5},
+		},
+		{
+			name: "insertion next to a deletion",
+			hunk: patch.Hunk{Text: "@@ -1,3 +1,4 @@\n a\n-b\n+x\n+y\n c\n"},
+			want: []int{2},
+		},
+		{
+			name: "removed file",
+			hunk: patch.Hunk{Header: "diff --git a/a b/a\ndeleted file mode 100644\n--- a/a\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+			want: []int{1, 2},
+		},
+		{
+			name: "new file",
+			hunk: patch.Hunk{Header: "diff --git a/a b/a\nnew file mode 100644\n--- /dev/null\n+++ b/a\n@@ -0,0 +1 @@\n+a\n"},
+		},
+		{
+			name: "binary file",
+			hunk: patch.Hunk{Header: "diff --git a/a b/a\nBinary files a/a and b/a differ\n", Binary: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.hunk); len(got) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Lines() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	got := Rank(map[string]int{"aaa": 2, "bbb": 5, "ccc": 2, "ddd": 0}, []string{"ccc", "bbb", "aaa"})
+	want := []Candidate{{Hash: "bbb", Lines: 5}, {Hash: "ccc", Lines: 2}, {Hash: "aaa", Lines: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() = %+v, want %+v", got, want)
+	}
+	if Ambiguous(got) {
+		t.Error("Ambiguous() = true with a clear leader")
+	}
+	if !Ambiguous(got[1:]) {
+		t.Error("Ambiguous() = false with a tie")
+	}
+	if Ambiguous(got[:1]) {
+		t.Error("Ambiguous() = true with a single candidate")
+	}
+}
+
+func TestChoose(t *testing.T) {
+	candidates := []Candidate{
+		{Hash: "0123456789abcdef0123456789abcdef01234567"},
+		{Hash: "fedcba9876543210fedcba9876543210fedcba98"},
+	}
+	tests := []struct {
+		response string
+		want     int
+	}{
+		{"fedcba9", 1},
+		{"The change fixes `0123456` (feat: add parser).", 0},
+		{"FEDCBA98", 1},
+		{"a commit that was added", -1},
+		{"abc1234", -1},
+	}
+	for _, tt := range tests {
+		if got := Choose(tt.response, candidates); got != tt.want {
+			t.Errorf("Choose(%q) = %d, want %d", tt.response, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// blameHeader starts the porcelain entry of every blamed line:
+// <commit> <original line> <final line> [<lines in group>]
+var blameHeader = regexp.MustCompile(`^([0-9a-f]{40,64}) \d+ (\d+)`)
+
+// Blame returns the commit that last changed each of lines of path at rev,
+// keyed by line number
+func Blame(ctx context.Context, rev, path string, lines []int) (map[int]string, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	sorted := append([]int(nil), lines...)
+	sort.Ints(sorted)
+
+	args := []string{"blame", "--porcelain"}
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] <= sorted[j]+1 {
+			j++
+		}
+		args = append(args, "-L", fmt.Sprintf("%d,%d", sorted[i], sorted[j]))
+		i = j + 1
+	}
+	args = append(args, rev, "--", path)
+
+	res, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git blame %s: %w", path, describeExitError(err))
+	}
+	blamed := make(map[int]string)
+	for _, line := range strings.Split(string(res), "\n") {
+		m := blameHeader.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		blamed[n] = m[1]
+	}
+	return blamed, nil
+}
@@ -0,0 +1,45 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlame(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ctx := context.Background()
+	require.NoError(t, os.WriteFile("a.txt", []byte("1\n2\n3\n4\n"), 0644))
+	require.NoError(t, exec.Command("git", "add", ".").Run())
+	require.NoError(t, exec.Command("git", "commit", "-q", "-m", "one").Run())
+	first, err := git.RevParse(ctx, "HEAD")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile("a.txt", []byte("1\ntwo\n3\nfour\n"), 0644))
+	require.NoError(t, exec.Command("git", "commit", "-q", "-am", "two").Run())
+	second, err := git.RevParse(ctx, "HEAD")
+	require.NoError(t, err)
+
+	blamed, err := git.Blame(ctx, "HEAD", "a.txt", []int{4, 1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: first, 2: second, 4: second}, blamed)
+
+	_, err = git.Blame(ctx, "HEAD", "missing.txt", []int{1})
+	assert.Error(t, err)
+}
@@ -3,21 +3,93 @@ package git
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"os/exec"
 )
 
-func CommitWMessage(ctx context.Context, msg string) error {
-	cmd := exec.CommandContext(ctx, "git", "commit", "-em", msg)
+// CommitOptions are the git commit flags vibecheck passes through. The zero
+// value commits the index and opens the editor to review the message.
+type CommitOptions struct {
+	// NoEdit commits the message as is instead of opening the editor
+	NoEdit bool
+	// Amend replaces HEAD
+	Amend bool
+	// NoVerify skips the pre-commit and commit-msg hooks
+	NoVerify bool
+	// Sign GPG or SSH signs the commit, with SignKey when set
+	Sign    bool
+	SignKey string
+	// Author and Date override the author identity and date
+	Author string
+	Date   string
+	// AllowEmpty records a commit without changes
+	AllowEmpty bool
+	// Fixup creates a "fixup!" commit for the given commit, or an "amend!"
+	// commit for amend:<commit>; git then writes the message itself
+	Fixup string
+	// Quiet suppresses git's commit summary on stdout
+	Quiet bool
+	// Pathspecs limit the commit to the matching paths, as in git commit -- <paths>
+	Pathspecs []string
+}
+
+// args returns the git commit arguments for msg and the options
+func (o CommitOptions) args(msg string) []string {
+	args := []string{"commit"}
+	if o.Fixup != "" {
+		args = append(args, "--fixup="+o.Fixup)
+		// amend! commits open the editor on the target's message otherwise
+		if o.NoEdit {
+			args = append(args, "--no-edit")
+		}
+	} else {
+		args = append(args, "-m", msg)
+		if !o.NoEdit {
+			args = append(args, "--edit")
+		}
+	}
+	if o.Amend {
+		args = append(args, "--amend")
+	}
+	if o.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if o.Sign {
+		args = append(args, "--gpg-sign"+keySuffix(o.SignKey))
+	}
+	if o.Author != "" {
+		args = append(args, "--author="+o.Author)
+	}
+	if o.Date != "" {
+		args = append(args, "--date="+o.Date)
+	}
+	if o.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	if o.Quiet {
+		args = append(args, "--quiet")
+	}
+	if len(o.Pathspecs) > 0 {
+		args = append(args, "--")
+		args = append(args, o.Pathspecs...)
+	}
+	return args
+}
+
+func keySuffix(key string) string {
+	if key == "" {
+		return ""
+	}
+	return "=" + key
+}
+
+// CommitWMessage runs git commit with msg, attached to the terminal so the editor,
+// hooks and signing prompts work as usual
+func CommitWMessage(ctx context.Context, msg string, opts CommitOptions) error {
+	cmd := exec.CommandContext(ctx, "git", opts.args(msg)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	if err := cmd.Run(); err != nil {
-		fmt.Println(err)
-		return err
-	}
-	cmd.Run()
-	return nil
+	return cmd.Run()
 }
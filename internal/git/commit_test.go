@@ -1,12 +1,52 @@
 package git_test
 
 import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
 	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCommitWMessage(t *testing.T) {
-	// Skip this test as git commit -em opens an editor which will hang in tests
-	// The function requires interactive input which is not suitable for automated testing
-	// In a real scenario, this would require mocking the exec.Command or using a different approach
-	t.Skip("Skipping TestCommitWMessage - git commit -em requires interactive editor input")
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ctx := context.Background()
+	require.NoError(t, os.WriteFile("a.txt", []byte("one\n"), 0644))
+	require.NoError(t, os.WriteFile("b.txt", []byte("one\n"), 0644))
+	require.NoError(t, exec.Command("git", "add", ".").Run())
+
+	// The editor is opened unless NoEdit is set, which would hang here
+	require.NoError(t, git.CommitWMessage(ctx, "feat: add a", git.CommitOptions{
+		NoEdit:    true,
+		Author:    "Sam Roe <sam@example.com>",
+		Date:      "2024-01-02T03:04:05Z",
+		Pathspecs: []string{"a.txt"},
+	}))
+	out, err := exec.Command("git", "log", "-1", "--format=%s|%an|%aI", "--name-only").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "feat: add a|Sam Roe|2024-01-02T03:04:05+00:00\n\na.txt\n", string(out))
+
+	require.NoError(t, git.CommitWMessage(ctx, "feat: add a and b", git.CommitOptions{NoEdit: true, Amend: true}))
+	require.NoError(t, git.CommitWMessage(ctx, "chore: empty", git.CommitOptions{NoEdit: true, AllowEmpty: true, Quiet: true}))
+	require.NoError(t, git.CommitWMessage(ctx, "", git.CommitOptions{NoEdit: true, AllowEmpty: true, Fixup: "HEAD~1"}))
+	require.NoError(t, git.CommitWMessage(ctx, "", git.CommitOptions{NoEdit: true, AllowEmpty: true, Fixup: "amend:HEAD~2"}))
+
+	out, err = exec.Command("git", "log", "--format=%s").Output()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amend! feat: add a and b", "fixup! feat: add a and b", "chore: empty", "feat: add a and b"}, strings.Split(strings.TrimSpace(string(out)), "\n"))
 }
@@ -3,11 +3,124 @@ package git
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 )
 
-func StagedDiff(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "git", "diff", "--staged")
+// DiffOptions tune how git produces a diff. The zero value is plain git diff.
+type DiffOptions struct {
+	// Context is the number of context lines (-U), git's default of 3 when nil
+	Context *int
+	// FunctionContext shows the whole function around each change (-W)
+	FunctionContext bool
+
+	// IgnoreWhitespace drops changes that only touch whitespace (-w)
+	IgnoreWhitespace bool
+	// IgnoreSpaceChange ignores changes in the amount of whitespace (-b)
+	IgnoreSpaceChange bool
+	// IgnoreSpaceAtEOL ignores whitespace changes at line ends
+	IgnoreSpaceAtEOL bool
+	// IgnoreBlankLines ignores added or removed blank lines
+	IgnoreBlankLines bool
+
+	// NoRenames turns rename detection off
+	NoRenames bool
+	// RenameThreshold is the similarity for rename detection, e.g. "50%" (-M)
+	RenameThreshold string
+	// FindCopies detects copies, at CopyThreshold when set (-C)
+	FindCopies    bool
+	CopyThreshold string
+	// FindCopiesHarder also considers unmodified files as copy sources
+	FindCopiesHarder bool
+
+	// Algorithm is one of myers, minimal, patience or histogram
+	Algorithm string
+	// Submodule is how submodule changes are shown: short, log or diff
+	Submodule string
+
+	// StatOnly produces a diffstat and summary instead of the patch
+	StatOnly bool
+}
+
+// DiffAlgorithms and SubmoduleFormats are the values git accepts
+var (
+	DiffAlgorithms   = []string{"myers", "minimal", "patience", "histogram"}
+	SubmoduleFormats = []string{"short", "log", "diff"}
+)
+
+// args returns the git diff flags for the options
+func (o DiffOptions) args() []string {
+	var args []string
+	if o.StatOnly {
+		args = append(args, "--stat", "--summary")
+	}
+	if o.Context != nil {
+		args = append(args, fmt.Sprintf("-U%d", *o.Context))
+	}
+	if o.FunctionContext {
+		args = append(args, "--function-context")
+	}
+	if o.IgnoreWhitespace {
+		args = append(args, "-w")
+	}
+	if o.IgnoreSpaceChange {
+		args = append(args, "-b")
+	}
+	if o.IgnoreSpaceAtEOL {
+		args = append(args, "--ignore-space-at-eol")
+	}
+	if o.IgnoreBlankLines {
+		args = append(args, "--ignore-blank-lines")
+	}
+	switch {
+	case o.NoRenames:
+		args = append(args, "--no-renames")
+	case o.RenameThreshold != "":
+		args = append(args, "-M"+o.RenameThreshold)
+	}
+	if o.FindCopies {
+		args = append(args, "-C"+o.CopyThreshold)
+	}
+	if o.FindCopiesHarder {
+		args = append(args, "--find-copies-harder")
+	}
+	if o.Algorithm != "" {
+		args = append(args, "--diff-algorithm="+o.Algorithm)
+	}
+	if o.Submodule != "" {
+		args = append(args, "--submodule="+o.Submodule)
+	}
+	return args
+}
+
+// StagedDiff returns the staged changes, optionally limited by git pathspecs
+func StagedDiff(ctx context.Context, pathspecs ...string) (string, error) {
+	return StagedDiffWith(ctx, DiffOptions{}, pathspecs...)
+}
+
+// StagedDiffWith returns the staged changes produced with opts
+func StagedDiffWith(ctx context.Context, opts DiffOptions, pathspecs ...string) (string, error) {
+	return StagedDiffSince(ctx, "", opts, pathspecs...)
+}
+
+// EmptyTree is the hash of the tree without files, the base of a root commit
+const EmptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// StagedDiffSince returns the difference between base and the index, which
+// for base HEAD^ covers what amending HEAD would commit. An empty base is HEAD.
+func StagedDiffSince(ctx context.Context, base string, opts DiffOptions, pathspecs ...string) (string, error) {
+	args := append([]string{"diff", "--staged"}, opts.args()...)
+	if base != "" {
+		args = append(args, base)
+	}
+	if len(pathspecs) > 0 {
+		args = append(args, "--")
+		args = append(args, pathspecs...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
 
 	res, err := cmd.Output()
 	if err != nil {
@@ -16,3 +129,92 @@ func StagedDiff(ctx context.Context) (string, error) {
 
 	return string(res), nil
 }
+
+// RevisionDiff returns the changes of a revision range such as "main..topic",
+// or of a single commit, produced with opts
+func RevisionDiff(ctx context.Context, opts DiffOptions, rev string) (string, error) {
+	var args []string
+	if strings.Contains(rev, "..") {
+		args = append([]string{"diff"}, opts.args()...)
+		args = append(args, rev, "--")
+	} else {
+		// show handles root commits, which have no parent to diff against
+		args = append([]string{"show", "--format=", "--patch"}, opts.args()...)
+		args = append(args, rev, "--")
+	}
+	res, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("diff %s: %w", rev, describeExitError(err))
+	}
+	return string(res), nil
+}
+
+// PatchStat returns the diffstat and summary of a patch that need not belong
+// to the current repository
+func PatchStat(ctx context.Context, patch string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "apply", "--stat", "--summary", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	// Inside a repository apply only counts paths below the working directory
+	cmd.Dir = os.TempDir()
+	res, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("patch stat: %w", describeExitError(err))
+	}
+	return string(res), nil
+}
+
+// StageTracked stages the changes of tracked files like git commit -a does,
+// optionally limited by git pathspecs
+func StageTracked(ctx context.Context, pathspecs ...string) error {
+	args := []string{"add", "--update"}
+	if len(pathspecs) > 0 {
+		args = append(args, "--")
+		args = append(args, pathspecs...)
+	}
+	if _, err := exec.CommandContext(ctx, "git", args...).Output(); err != nil {
+		return fmt.Errorf("stage tracked changes: %w", describeExitError(err))
+	}
+	return nil
+}
+
+// StagedFiles returns the paths of the staged files, optionally limited by git pathspecs
+func StagedFiles(ctx context.Context, pathspecs ...string) ([]string, error) {
+	args := []string{"diff", "--staged", "--name-only", "-z"}
+	if len(pathspecs) > 0 {
+		args = append(args, "--")
+		args = append(args, pathspecs...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+
+	res, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, f := range strings.Split(string(res), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// FileAt returns the content of path at rev, or in the index when rev is
+// empty. The second return value is false when the file does not exist there.
+func FileAt(ctx context.Context, rev, path string) (string, bool, error) {
+	spec := rev + ":" + path
+	if err := exec.CommandContext(ctx, "git", "cat-file", "-e", spec).Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	res, err := exec.CommandContext(ctx, "git", "cat-file", "blob", spec).Output()
+	if err != nil {
+		return "", false, fmt.Errorf("read %s: %w", spec, describeExitError(err))
+	}
+	return string(res), true, nil
+}
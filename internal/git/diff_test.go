@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rshdhere/vibecheck/internal/git"
@@ -185,3 +187,153 @@ func TestStagedDiffWithModifications(t *testing.T) {
 	assert.Contains(t, changes, "test.txt")
 	assert.Contains(t, changes, "modified content")
 }
+
+func TestStagedDiffPathspecs(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	require.NoError(t, os.WriteFile(fmt.Sprintf("%s/main.go", repo), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(fmt.Sprintf("%s/go.sum", repo), []byte("example.com/mod v1.0.0 h1:abc\n"), 0644))
+
+	cmd := exec.Command("git", "add", "main.go", "go.sum")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+
+	changes, err := git.StagedDiff(context.Background(), "main.go")
+	assert.NoError(t, err)
+	assert.Contains(t, changes, "main.go")
+	assert.NotContains(t, changes, "go.sum")
+}
+
+func TestStagedDiffOptions(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	content := "one\ntwo\nthree\nfour\nfive\nsix\nseven\n"
+	require.NoError(t, os.WriteFile(fmt.Sprintf("%s/lines.txt", repo), []byte(content), 0644))
+
+	cmd := exec.Command("git", "add", "lines.txt")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+
+	stat, err := git.StagedDiffWith(context.Background(), git.DiffOptions{StatOnly: true})
+	assert.NoError(t, err)
+	assert.Contains(t, stat, "lines.txt | 7 +")
+	assert.Contains(t, stat, "create mode 100644 lines.txt")
+	assert.NotContains(t, stat, "+three")
+
+	one := 1
+	histogram, err := git.StagedDiffWith(context.Background(), git.DiffOptions{Context: &one, Algorithm: "histogram", NoRenames: true})
+	assert.NoError(t, err)
+	assert.Contains(t, histogram, "+three")
+
+	require.NoError(t, exec.Command("git", "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "lines").Run())
+	require.NoError(t, os.WriteFile("lines.txt", []byte(strings.Replace(content, "four", "4", 1)), 0644))
+	require.NoError(t, exec.Command("git", "add", "lines.txt").Run())
+
+	zero := 0
+	bare, err := git.StagedDiffWith(context.Background(), git.DiffOptions{Context: &zero})
+	assert.NoError(t, err)
+	assert.Contains(t, bare, "@@ -4 +4 @@")
+	assert.NotContains(t, bare, "\n three")
+}
+
+func TestStageTracked(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+
+	require.NoError(t, os.WriteFile("tracked.txt", []byte("one\n"), 0644))
+	require.NoError(t, exec.Command("git", "add", "tracked.txt").Run())
+	require.NoError(t, os.WriteFile("tracked.txt", []byte("two\n"), 0644))
+	require.NoError(t, os.WriteFile("untracked.txt", []byte("new\n"), 0644))
+
+	require.NoError(t, git.StageTracked(context.Background()))
+
+	changes, err := git.StagedDiff(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, changes, "+two")
+	assert.NotContains(t, changes, "untracked.txt")
+}
+
+func TestPatchStat(t *testing.T) {
+	patch := "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -1 +1,2 @@\n-a\n+b\n+c\n"
+	stat, err := git.PatchStat(context.Background(), patch)
+	assert.NoError(t, err)
+	assert.Contains(t, stat, "1 file changed, 2 insertions(+), 1 deletion(-)")
+}
+
+func TestStagedFiles(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	require.NoError(t, os.MkdirAll(fmt.Sprintf("%s/cmd", repo), 0755))
+	require.NoError(t, os.WriteFile(fmt.Sprintf("%s/cmd/commit.go", repo), []byte("package cmd\n"), 0644))
+	require.NoError(t, os.WriteFile(fmt.Sprintf("%s/unstaged.txt", repo), []byte("nope\n"), 0644))
+
+	cmd := exec.Command("git", "add", "cmd/commit.go")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+
+	files, err := git.StagedFiles(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cmd/commit.go"}, files)
+}
+
+func TestFileAt(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	ctx := context.Background()
+
+	_, ok, err := git.FileAt(ctx, "HEAD", "go.mod")
+	require.NoError(t, err)
+	assert.False(t, ok, "unborn HEAD has no files")
+
+	commitFile(t, filepath.Join(repo, "go.mod"), "module old")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module new\n"), 0644))
+	require.NoError(t, exec.Command("git", "add", "go.mod").Run())
+
+	committed, ok, err := git.FileAt(ctx, "HEAD", "go.mod")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "module old\n", committed)
+
+	staged, ok, err := git.FileAt(ctx, "", "go.mod")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "module new\n", staged)
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// StagedPatch returns the staged changes as a patch git apply can replay,
+// binary files included, regardless of the user's diff settings
+func StagedPatch(ctx context.Context) (string, error) {
+	res, err := exec.CommandContext(ctx, "git", "diff", "--staged", "--binary", "--full-index", "--no-color", "--no-ext-diff").Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff --staged: %w", describeExitError(err))
+	}
+	return string(res), nil
+}
+
+// WriteTree records the index as a tree and returns its hash, so the index can
+// be put back exactly with ReadTree
+func WriteTree(ctx context.Context) (string, error) {
+	res, err := exec.CommandContext(ctx, "git", "write-tree").Output()
+	if err != nil {
+		return "", fmt.Errorf("git write-tree: %w", describeExitError(err))
+	}
+	return strings.TrimSpace(string(res)), nil
+}
+
+// ReadTree replaces the index with treeish, leaving the working tree alone
+func ReadTree(ctx context.Context, treeish string) error {
+	if out, err := exec.CommandContext(ctx, "git", "read-tree", treeish).CombinedOutput(); err != nil {
+		return fmt.Errorf("git read-tree %s: %s", treeish, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ApplyCached applies patch to the index only, as git apply --cached does
+func ApplyCached(ctx context.Context, patch string) error {
+	cmd := exec.CommandContext(ctx, "git", "apply", "--cached", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git apply --cached: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
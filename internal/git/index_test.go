@@ -0,0 +1,53 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCached(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ctx := context.Background()
+	require.NoError(t, os.WriteFile("a.txt", []byte("a\n"), 0644))
+	require.NoError(t, exec.Command("git", "add", ".").Run())
+	require.NoError(t, exec.Command("git", "commit", "-q", "-m", "init").Run())
+
+	require.NoError(t, os.WriteFile("a.txt", []byte("a\nb\n"), 0644))
+	require.NoError(t, os.WriteFile("logo.bin", []byte{0, 1, 2, 0}, 0644))
+	require.NoError(t, exec.Command("git", "add", ".").Run())
+	staged, err := git.WriteTree(ctx)
+	require.NoError(t, err)
+	patch, err := git.StagedPatch(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, patch, "GIT binary patch")
+
+	require.NoError(t, git.ReadTree(ctx, "HEAD"))
+	files, err := git.StagedFiles(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, git.ApplyCached(ctx, patch))
+	tree, err := git.WriteTree(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, staged, tree)
+
+	assert.Error(t, git.ApplyCached(ctx, patch), "applying twice")
+	require.NoError(t, git.ReadTree(ctx, staged))
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Commit is a commit as listed by Log
+type Commit struct {
+	Hash    string
+	Subject string
+	Message string
+}
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Log lists the commits selected by revision arguments such as "main..HEAD",
+// newest first
+func Log(ctx context.Context, revs ...string) ([]Commit, error) {
+	args := []string{"log", "--format=%H" + fieldSep + "%s" + fieldSep + "%B" + recordSep}
+	args = append(args, revs...)
+	args = append(args, "--")
+	cmd := exec.CommandContext(ctx, "git", args...)
+
+	res, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", describeExitError(err))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(res), recordSep) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		parts := strings.SplitN(record, fieldSep, 3)
+		if len(parts) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Hash:    parts[0],
+			Subject: parts[1],
+			Message: strings.TrimRight(parts[2], "\n"),
+		})
+	}
+	return commits, nil
+}
+
+// RevParse resolves a revision to a full object name
+func RevParse(ctx context.Context, rev string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+
+	res, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	return strings.TrimSpace(string(res)), nil
+}
+
+// Parent returns the first parent of rev, or EmptyTree when rev is a root commit
+func Parent(ctx context.Context, rev string) (string, error) {
+	if _, err := RevParse(ctx, rev); err != nil {
+		return "", err
+	}
+	if parent, err := RevParse(ctx, rev+"^"); err == nil {
+		return parent, nil
+	}
+	return EmptyTree, nil
+}
+
+// MergeBase returns the best common ancestor of a and b
+func MergeBase(ctx context.Context, a, b string) (string, error) {
+	res, err := exec.CommandContext(ctx, "git", "merge-base", a, b).Output()
+	if err != nil {
+		return "", fmt.Errorf("merge-base %s %s: %w", a, b, describeExitError(err))
+	}
+	return strings.TrimSpace(string(res)), nil
+}
+
+// describeExitError adds git's stderr output to an exit error
+func describeExitError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitFile writes name in the current repository and commits it with message
+func commitFile(t *testing.T, name, message string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(name, []byte(message+"\n"), 0644))
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+	require.NoError(t, exec.Command("git", "add", name).Run())
+	require.NoError(t, exec.Command("git", "commit", "-q", "-m", message).Run())
+}
+
+func TestLog(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	commitFile(t, filepath.Join(repo, "a.txt"), "feat: first")
+	commitFile(t, filepath.Join(repo, "b.txt"), "fix: second\n\nwith a body")
+
+	ctx := context.Background()
+	commits, err := git.Log(ctx, "HEAD")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "fix: second", commits[0].Subject)
+	assert.Equal(t, "fix: second\n\nwith a body", commits[0].Message)
+	assert.Equal(t, "feat: first", commits[1].Subject)
+
+	head, err := git.RevParse(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, commits[0].Hash, head)
+
+	only, err := git.Log(ctx, "HEAD~1..HEAD")
+	require.NoError(t, err)
+	assert.Len(t, only, 1)
+
+	_, err = git.RevParse(ctx, "no-such-branch")
+	assert.Error(t, err)
+}
+
+func TestNotes(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	commitFile(t, filepath.Join(repo, "a.txt"), "feat: first")
+
+	ctx := context.Background()
+	const ref = "refs/notes/test"
+
+	_, ok, err := git.ReadNote(ctx, ref, "HEAD")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	noted, err := git.NotedCommits(ctx, ref)
+	require.NoError(t, err)
+	assert.Empty(t, noted)
+
+	require.NoError(t, git.AddNote(ctx, ref, "HEAD", "hello\n"))
+	require.NoError(t, git.AddNote(ctx, ref, "HEAD", "replaced\n"))
+
+	note, ok, err := git.ReadNote(ctx, ref, "HEAD")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "replaced\n", note)
+
+	head, err := git.RevParse(ctx, "HEAD")
+	require.NoError(t, err)
+	noted, err = git.NotedCommits(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{head: true}, noted)
+}
@@ -0,0 +1,66 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// AddNote attaches content as a note to rev under the given notes ref,
+// replacing any note already there
+func AddNote(ctx context.Context, ref, rev, content string) error {
+	cmd := exec.CommandContext(ctx, "git", "notes", "--ref", ref, "add", "-f", "-F", "-", rev)
+	cmd.Stdin = strings.NewReader(content)
+
+	if _, err := cmd.Output(); err != nil {
+		return fmt.Errorf("add note: %w", describeExitError(err))
+	}
+	return nil
+}
+
+// ReadNote returns the note attached to rev under ref. The second return value
+// is false when the commit has no note.
+func ReadNote(ctx context.Context, ref, rev string) (string, bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "notes", "--ref", ref, "show", rev)
+
+	res, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "no note found") {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("read note: %w", describeExitError(err))
+	}
+	return string(res), true, nil
+}
+
+// CopyNote attaches the note of from under ref to to as well, replacing any
+// note to already has
+func CopyNote(ctx context.Context, ref, from, to string) error {
+	cmd := exec.CommandContext(ctx, "git", "notes", "--ref", ref, "copy", "-f", from, to)
+
+	if _, err := cmd.Output(); err != nil {
+		return fmt.Errorf("copy note: %w", describeExitError(err))
+	}
+	return nil
+}
+
+// NotedCommits returns the set of commits that carry a note under ref
+func NotedCommits(ctx context.Context, ref string) (map[string]bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "notes", "--ref", ref, "list")
+
+	res, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", describeExitError(err))
+	}
+
+	noted := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(string(res)), "\n") {
+		if _, commit, ok := strings.Cut(line, " "); ok {
+			noted[commit] = true
+		}
+	}
+	return noted, nil
+}
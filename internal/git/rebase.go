@@ -0,0 +1,97 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateRef points ref at rev, creating it when needed
+func UpdateRef(ctx context.Context, ref, rev string) error {
+	if out, err := exec.CommandContext(ctx, "git", "update-ref", ref, rev).CombinedOutput(); err != nil {
+		return fmt.Errorf("git update-ref %s: %s", ref, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Reword rewrites the messages of commits, the full hashes between base and
+// HEAD oldest first, with a scripted interactive rebase. Commits missing from
+// messages are picked unchanged; authorship is kept. An empty base rewrites
+// from the root commit. Merge commits are not supported. A rebase that stops
+// is aborted, which leaves the branch as it was.
+func Reword(ctx context.Context, base string, commits []string, messages map[string]string) error {
+	dir, err := os.MkdirTemp("", "vibecheck-reword-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var todo strings.Builder
+	for i, hash := range commits {
+		fmt.Fprintf(&todo, "pick %s\n", hash)
+		msg, ok := messages[hash]
+		if !ok {
+			continue
+		}
+		file := filepath.Join(dir, fmt.Sprintf("message-%d", i))
+		if err := os.WriteFile(file, []byte(msg), 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(&todo, "exec git commit --amend --quiet --no-verify --allow-empty --cleanup=whitespace -F %s\n", shellQuote(file))
+	}
+	todoFile := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoFile, []byte(todo.String()), 0600); err != nil {
+		return err
+	}
+
+	args := []string{"rebase", "--interactive", "--quiet"}
+	if base == "" {
+		args = append(args, "--root")
+	} else {
+		args = append(args, base)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// git hands the todo list to the sequence editor, which replaces it with ours
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoFile), "GIT_EDITOR=true")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		err = fmt.Errorf("git rebase: %w: %s", err, strings.TrimSpace(string(out)))
+		if rebaseInProgress(ctx) {
+			if abortErr := exec.CommandContext(ctx, "git", "rebase", "--abort").Run(); abortErr != nil {
+				return fmt.Errorf("%w; finish or undo it with git rebase --abort", err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// rebaseInProgress reports whether a rebase has stopped and waits to be
+// continued or aborted
+func rebaseInProgress(ctx context.Context) bool {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		res, err := exec.CommandContext(ctx, "git", "rev-parse", "--git-path", dir).Output()
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(strings.TrimSpace(string(res))); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ResetSoft moves the current branch to rev and keeps the index and working
+// tree, so the changes since rev are staged
+func ResetSoft(ctx context.Context, rev string) error {
+	if out, err := exec.CommandContext(ctx, "git", "reset", "--soft", rev).CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset --soft %s: %s", rev, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
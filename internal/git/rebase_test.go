@@ -0,0 +1,124 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReword(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, os.WriteFile(name+".txt", []byte(name+"\n"), 0644))
+		require.NoError(t, exec.Command("git", "add", ".").Run())
+		require.NoError(t, exec.Command("git", "commit", "-q", "--author", "Sam Roe <sam@example.com>", "-m", "wip "+name).Run())
+	}
+	commits, err := git.Log(ctx, "--reverse", "HEAD")
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+
+	require.NoError(t, git.UpdateRef(ctx, "refs/vibecheck/backup/test", "HEAD"))
+	hashes := []string{commits[0].Hash, commits[1].Hash, commits[2].Hash}
+	require.NoError(t, git.Reword(ctx, "", hashes, map[string]string{
+		commits[0].Hash: "feat: add a\n\nWith a body.\n",
+		commits[2].Hash: "feat: add c\n",
+	}))
+
+	out, err := exec.Command("git", "log", "--format=%s|%an").Output()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feat: add c|Sam Roe", "wip b|Sam Roe", "feat: add a|Sam Roe"}, strings.Split(strings.TrimSpace(string(out)), "\n"))
+
+	backup, err := git.RevParse(ctx, "refs/vibecheck/backup/test")
+	require.NoError(t, err)
+	assert.Equal(t, commits[2].Hash, backup)
+
+	// Rewording a series on top of a base leaves the base alone
+	head, err := git.Log(ctx, "-1", "HEAD")
+	require.NoError(t, err)
+	require.NoError(t, git.Reword(ctx, "HEAD~1", []string{head[0].Hash}, map[string]string{head[0].Hash: "feat: add the c file\n"}))
+	out, err = exec.Command("git", "log", "--format=%s").Output()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feat: add the c file", "wip b", "feat: add a"}, strings.Split(strings.TrimSpace(string(out)), "\n"))
+}
+
+func TestRewordAbortsOnConflict(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ctx := context.Background()
+	commit := func(content, msg string) string {
+		require.NoError(t, os.WriteFile("a.txt", []byte(content), 0644))
+		require.NoError(t, exec.Command("git", "add", ".").Run())
+		require.NoError(t, exec.Command("git", "commit", "-q", "-m", msg).Run())
+		hash, err := git.RevParse(ctx, "HEAD")
+		require.NoError(t, err)
+		return hash
+	}
+	base := commit("a\n", "init")
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-b", "side").Run())
+	side := commit("x\n", "side")
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-").Run())
+	head := commit("y\n", "main")
+
+	// Picking the side commit first makes the main commit conflict
+	err = git.Reword(ctx, base, []string{side, head}, map[string]string{head: "feat: y\n"})
+	require.Error(t, err)
+
+	current, err := git.RevParse(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, head, current)
+	_, err = os.Stat(".git/rebase-merge")
+	assert.True(t, os.IsNotExist(err), "rebase left in progress")
+}
+
+func TestResetSoft(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ctx := context.Background()
+	require.NoError(t, exec.Command("git", "commit", "-q", "--allow-empty", "-m", "init").Run())
+	require.NoError(t, os.WriteFile("a.txt", []byte("a\n"), 0644))
+	require.NoError(t, exec.Command("git", "add", ".").Run())
+	require.NoError(t, exec.Command("git", "commit", "-q", "-m", "wip").Run())
+
+	require.NoError(t, git.ResetSoft(ctx, "HEAD~1"))
+	files, err := git.StagedFiles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt"}, files)
+}
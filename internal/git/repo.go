@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// TopLevel returns the absolute path of the root of the current working tree
+func TopLevel(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel")
+
+	res, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(res)), nil
+}
+
+// RemoteURLs returns the fetch URLs of every configured remote
+func RemoteURLs(ctx context.Context) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "config", "--get-regexp", `^remote\..*\.url$`)
+
+	res, err := cmd.Output()
+	if err != nil {
+		// git config exits with 1 when nothing matches
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var urls []string
+	for _, line := range strings.Split(strings.TrimSpace(string(res)), "\n") {
+		if _, url, ok := strings.Cut(line, " "); ok {
+			urls = append(urls, url)
+		}
+	}
+	return urls, nil
+}
+
+// CurrentBranch returns the short name of the checked out branch, or an empty
+// string when HEAD is detached
+func CurrentBranch(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+
+	res, err := cmd.Output()
+	if err != nil {
+		// rev-parse fails on a branch without commits, symbolic-ref still knows its name
+		fallback := exec.CommandContext(ctx, "git", "symbolic-ref", "--short", "HEAD")
+		if res, err = fallback.Output(); err != nil {
+			return "", err
+		}
+	}
+
+	branch := strings.TrimSpace(string(res))
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// HooksDir returns the directory git runs hooks from, honouring core.hooksPath
+func HooksDir(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+
+	res, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(res)), nil
+}
+
+// Clean reports whether the index and the tracked files match HEAD
+func Clean(ctx context.Context) (bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=no")
+
+	res, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("git status: %w", describeExitError(err))
+	}
+
+	return strings.TrimSpace(string(res)) == "", nil
+}
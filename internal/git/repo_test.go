@@ -0,0 +1,141 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopLevel(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	sub := filepath.Join(repo, "nested", "dir")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(sub)
+	root, err := git.TopLevel(context.Background())
+	require.NoError(t, err)
+
+	want, err := filepath.EvalSymlinks(repo)
+	require.NoError(t, err)
+	got, err := filepath.EvalSymlinks(root)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestTopLevelOutsideRepo(t *testing.T) {
+	dir := t.TempDir()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(dir)
+	_, err = git.TopLevel(context.Background())
+	assert.Error(t, err)
+}
+
+func TestRemoteURLs(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+
+	urls, err := git.RemoteURLs(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, urls)
+
+	cmd := exec.Command("git", "remote", "add", "origin", "git@github.com:acme/payments.git")
+	require.NoError(t, cmd.Run())
+
+	urls, err = git.RemoteURLs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"git@github.com:acme/payments.git"}, urls)
+}
+
+func TestCurrentBranch(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-b", "feature/PAY-1234-refund-flow").Run())
+	require.NoError(t, exec.Command("git", "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init").Run())
+
+	branch, err := git.CurrentBranch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "feature/PAY-1234-refund-flow", branch)
+
+	require.NoError(t, exec.Command("git", "checkout", "-q", "--detach").Run())
+	branch, err = git.CurrentBranch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "", branch)
+}
+
+func TestCurrentBranchUnborn(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+
+	require.NoError(t, exec.Command("git", "checkout", "-q", "-b", "feature/NEW-1").Run())
+	branch, err := git.CurrentBranch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "feature/NEW-1", branch)
+}
+
+func TestHooksDir(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	dir, err := git.HooksDir(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(".git", "hooks"), relativeTo(t, repo, dir))
+
+	require.NoError(t, exec.Command("git", "config", "core.hooksPath", ".githooks").Run())
+	dir, err = git.HooksDir(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ".githooks", relativeTo(t, repo, dir))
+}
+
+func relativeTo(t *testing.T, base, path string) string {
+	t.Helper()
+	base, err := filepath.EvalSymlinks(base)
+	require.NoError(t, err)
+	rel, err := filepath.Rel(base, path)
+	require.NoError(t, err)
+	return rel
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Tag is a tag and the commit it points at
+type Tag struct {
+	Name   string
+	Commit string
+	// Date is the tagger date of annotated tags and the commit date otherwise, as YYYY-MM-DD
+	Date string
+}
+
+// Tags lists the tags reachable from merged but not from notMerged, newest
+// first. An empty merged lists tags on every branch, and an empty notMerged
+// does not exclude any.
+func Tags(ctx context.Context, merged, notMerged string) ([]Tag, error) {
+	args := []string{"for-each-ref", "--sort=-creatordate",
+		"--format=%(refname:short)" + fieldSep + "%(objectname)" + fieldSep + "%(*objectname)" + fieldSep + "%(creatordate:short)"}
+	if merged != "" {
+		args = append(args, "--merged="+merged)
+	}
+	if notMerged != "" {
+		args = append(args, "--no-merged="+notMerged)
+	}
+	args = append(args, "refs/tags")
+
+	res, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref: %w", describeExitError(err))
+	}
+
+	var tags []Tag
+	for _, line := range strings.Split(strings.TrimSpace(string(res)), "\n") {
+		parts := strings.Split(line, fieldSep)
+		if len(parts) != 4 {
+			continue
+		}
+		t := Tag{Name: parts[0], Commit: parts[1], Date: parts[3]}
+		// Annotated tags point at a tag object; the commit is what it peels to
+		if parts[2] != "" {
+			t.Commit = parts[2]
+		}
+		tags = append(tags, t)
+	}
+	return tags, nil
+}
+
+// LatestTag returns the most recent tag reachable from rev, or an empty
+// string when there is none
+func LatestTag(ctx context.Context, rev string) (string, error) {
+	if _, err := RevParse(ctx, rev); err != nil {
+		return "", err
+	}
+	res, err := exec.CommandContext(ctx, "git", "describe", "--tags", "--abbrev=0", rev).Output()
+	if err != nil {
+		return "", nil
+	}
+	return strings.TrimSpace(string(res)), nil
+}
+
+// CreateTag creates an annotated tag name at HEAD with message, kept verbatim
+// so that Markdown headings survive
+func CreateTag(ctx context.Context, name, message string) error {
+	cmd := exec.CommandContext(ctx, "git", "tag", "--annotate", "--cleanup=verbatim", "--file=-", name)
+	cmd.Stdin = strings.NewReader(message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git tag %s: %s", name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ctx := context.Background()
+	latest, err := git.LatestTag(ctx, "HEAD")
+	assert.Error(t, err, "no commits yet")
+	assert.Empty(t, latest)
+
+	commit := func(msg, date string) {
+		cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", msg)
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date, "GIT_AUTHOR_DATE="+date)
+		require.NoError(t, cmd.Run())
+	}
+	commit("feat: one", "2024-01-01T00:00:00Z")
+	latest, err = git.LatestTag(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Empty(t, latest)
+
+	require.NoError(t, exec.Command("git", "tag", "v1.0.0").Run())
+	commit("feat: two", "2024-02-01T00:00:00Z")
+	tag := exec.Command("git", "tag", "-a", "-m", "release", "v1.1.0")
+	tag.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2024-02-02T00:00:00Z")
+	require.NoError(t, tag.Run())
+	commit("fix: three", "2024-03-01T00:00:00Z")
+
+	head1, err := git.RevParse(ctx, "HEAD~1")
+	require.NoError(t, err)
+	tags, err := git.Tags(ctx, "HEAD", "")
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, git.Tag{Name: "v1.1.0", Commit: head1, Date: "2024-02-02"}, tags[0])
+	assert.Equal(t, "v1.0.0", tags[1].Name)
+	assert.Equal(t, "2024-01-01", tags[1].Date)
+
+	tags, err = git.Tags(ctx, "HEAD", "v1.0.0")
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "v1.1.0", tags[0].Name)
+
+	latest, err = git.LatestTag(ctx, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", latest)
+
+	// Every tag, not only those on the current branch
+	require.NoError(t, git.CreateTag(ctx, "v1.2.0-rc.1", "v1.2.0-rc.1\n\n### Fixed\n\n- three\n"))
+	out, err := exec.Command("git", "tag", "-l", "--format=%(contents)", "v1.2.0-rc.1").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0-rc.1\n\n### Fixed\n\n- three\n", strings.TrimRight(string(out), "\n")+"\n")
+	require.NoError(t, exec.Command("git", "checkout", "-q", "v1.0.0").Run())
+	tags, err = git.Tags(ctx, "", "")
+	require.NoError(t, err)
+	assert.Len(t, tags, 3)
+	assert.Error(t, git.CreateTag(ctx, "v1.0.0", "again"))
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Trailer is a single "Token: value" line in the footer of a commit message
+type Trailer struct {
+	Token string
+	Value string
+}
+
+func (t Trailer) String() string {
+	return t.Token + ": " + t.Value
+}
+
+// ParseTrailer parses a "Token: value" line
+func ParseTrailer(line string) (Trailer, bool) {
+	token, value, ok := strings.Cut(line, ":")
+	token, value = strings.TrimSpace(token), strings.TrimSpace(value)
+	if !ok || token == "" || value == "" || strings.ContainsAny(token, " \t") {
+		return Trailer{}, false
+	}
+	return Trailer{Token: token, Value: value}, true
+}
+
+// InterpretTrailers adds trailers to message with git interpret-trailers, so
+// they land in the message's footer block and identical trailers are never
+// added twice
+func InterpretTrailers(ctx context.Context, message string, trailers []Trailer) (string, error) {
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	args := []string{"interpret-trailers", "--if-exists", "addIfDifferent"}
+	for _, t := range trailers {
+		args = append(args, "--trailer", t.String())
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdin = strings.NewReader(strings.TrimRight(message, "\n") + "\n")
+
+	res, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("interpret trailers: %w", err)
+	}
+	return strings.TrimRight(string(res), "\n"), nil
+}
+
+// ParseMessageTrailers returns the trailers already present in message
+func ParseMessageTrailers(ctx context.Context, message string) ([]Trailer, error) {
+	cmd := exec.CommandContext(ctx, "git", "interpret-trailers", "--parse")
+	cmd.Stdin = strings.NewReader(message)
+
+	res, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("parse trailers: %w", err)
+	}
+
+	var trailers []Trailer
+	for _, line := range strings.Split(string(res), "\n") {
+		if t, ok := ParseTrailer(line); ok {
+			trailers = append(trailers, t)
+		}
+	}
+	return trailers, nil
+}
+
+// CommitterIdent returns "Name <email>" of the committer the same way
+// git commit --signoff determines it
+func CommitterIdent(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "var", "GIT_COMMITTER_IDENT")
+
+	res, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("committer identity: %w", err)
+	}
+
+	// The ident ends with "<email> <timestamp> <timezone>"
+	ident := strings.TrimSpace(string(res))
+	end := strings.LastIndex(ident, ">")
+	if end < 0 {
+		return "", fmt.Errorf("committer identity: unexpected format %q", ident)
+	}
+	return ident[:end+1], nil
+}
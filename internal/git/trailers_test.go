@@ -0,0 +1,70 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrailer(t *testing.T) {
+	tr, ok := git.ParseTrailer("Signed-off-by: Jane Doe <jane@example.com>")
+	assert.True(t, ok)
+	assert.Equal(t, git.Trailer{Token: "Signed-off-by", Value: "Jane Doe <jane@example.com>"}, tr)
+
+	_, ok = git.ParseTrailer("not a trailer")
+	assert.False(t, ok)
+
+	_, ok = git.ParseTrailer("Two words: value")
+	assert.False(t, ok)
+}
+
+func TestInterpretTrailers(t *testing.T) {
+	ctx := context.Background()
+	message := "feat(api): add refund flow\n\n- handle partial refunds"
+	trailers := []git.Trailer{
+		{Token: "Signed-off-by", Value: "Jane Doe <jane@example.com>"},
+		{Token: "Co-authored-by", Value: "John Roe <john@example.com>"},
+	}
+
+	got, err := git.InterpretTrailers(ctx, message, trailers)
+	require.NoError(t, err)
+	assert.Equal(t, message+"\n\nSigned-off-by: Jane Doe <jane@example.com>\nCo-authored-by: John Roe <john@example.com>", got)
+
+	// Running again must not duplicate anything
+	again, err := git.InterpretTrailers(ctx, got, trailers)
+	require.NoError(t, err)
+	assert.Equal(t, got, again)
+
+	parsed, err := git.ParseMessageTrailers(ctx, again)
+	require.NoError(t, err)
+	assert.Equal(t, trailers, parsed)
+}
+
+func TestInterpretTrailersNone(t *testing.T) {
+	got, err := git.InterpretTrailers(context.Background(), "fix: typo\n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fix: typo\n", got)
+}
+
+func TestCommitterIdent(t *testing.T) {
+	repo, err := SetupGitRepo()
+	require.NoError(t, err)
+	defer os.RemoveAll(repo)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(wd)
+
+	os.Chdir(repo)
+	require.NoError(t, exec.Command("git", "config", "user.name", "Jane Doe").Run())
+	require.NoError(t, exec.Command("git", "config", "user.email", "jane@example.com").Run())
+
+	ident, err := git.CommitterIdent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe <jane@example.com>", ident)
+}
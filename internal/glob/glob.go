@@ -0,0 +1,133 @@
+// Package glob matches slash separated paths against shell style patterns
+// where "**" may span directories
+package glob
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	mu    sync.Mutex
+	cache = map[string]*regexp.Regexp{}
+)
+
+// Match reports whether name matches pattern. "*" and "?" never cross a "/",
+// "**" matches any number of directories, and "[...]" is a character class.
+// Invalid patterns, such as those with a reversed range, match nothing; check
+// patterns with Validate when they are read.
+func Match(pattern, name string) bool {
+	re, err := compile(pattern)
+	return err == nil && re.MatchString(name)
+}
+
+// Validate reports why pattern cannot be used, e.g. "[z-a]"
+func Validate(pattern string) error {
+	_, err := compile(pattern)
+	return err
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if re, ok := cache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile("^" + translate(pattern) + "$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	cache[pattern] = re
+	return re, nil
+}
+
+// translate converts a glob into an equivalent regular expression body
+func translate(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+					continue
+				}
+				b.WriteString(".*")
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			class, n, ok := translateClass(pattern[i+1:])
+			if !ok {
+				// As in the shell, a "[" that opens no class is literal
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString(class)
+			i += n
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// translateClass converts the character class after a "[" and returns the
+// number of bytes it used, including the closing "]". A "]" right after the
+// "[" or "[!" is a member, and [:alpha:] style classes are kept as they are.
+// It fails when the class is not closed.
+func translateClass(rest string) (string, int, bool) {
+	var b strings.Builder
+	b.WriteString("[")
+	i := 0
+	if strings.HasPrefix(rest, "!") || strings.HasPrefix(rest, "^") {
+		b.WriteString("^")
+		i++
+	}
+	for first := true; i < len(rest); first = false {
+		c := rest[i]
+		switch {
+		case c == ']' && !first:
+			b.WriteString("]")
+			return b.String(), i + 1, true
+		case c == '[' && strings.HasPrefix(rest[i:], "[:"):
+			end := strings.Index(rest[i+2:], ":]")
+			if end < 0 {
+				b.WriteString(`\[`)
+				i++
+				continue
+			}
+			b.WriteString(rest[i : i+2+end+2])
+			i += 2 + end + 2
+			continue
+		case c == '\\' && i+1 < len(rest):
+			// A backslash escapes the next character, which may be a "]"
+			i++
+			b.WriteString(classLiteral(rest[i]))
+		case c == '\\' || c == '[' || c == ']' || c == '^':
+			b.WriteString(`\` + string(c))
+		default:
+			b.WriteByte(c)
+		}
+		i++
+	}
+	return "", 0, false
+}
+
+// classLiteral escapes c for use inside a regular expression class
+func classLiteral(c byte) string {
+	if c < utf8.RuneSelf && !unicode.IsLetter(rune(c)) && !unicode.IsDigit(rune(c)) {
+		return `\` + string(c)
+	}
+	return string(c)
+}
@@ -0,0 +1,53 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"internal/llm/**", "internal/llm/openai/client.go", true},
+		{"internal/llm/**", "internal/llmx/client.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/commit.go", true},
+		{"*.go", "cmd/commit.go", false},
+		{"cmd/*.go", "cmd/commit.go", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file/.txt", false},
+		{"[abc].md", "b.md", true},
+		{"[!abc].md", "b.md", false},
+		{"github.com/acme/*", "github.com/acme/payments", true},
+		{"a+b(c).txt", "a+b(c).txt", true},
+		{"[unterminated", "[unterminated", true},
+		{"[]", "[]", true},
+		{"[!]", "[!]", true},
+		{"[]abc].md", "].md", true},
+		{"[]abc].md", "b.md", true},
+		{"[!]].md", "].md", false},
+		{"[[:alpha:]].go", "a.go", true},
+		{"[[:alpha:]].go", "1.go", false},
+		{"[a\\]].md", "].md", true},
+		{"[z-a].go", "b.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, pattern := range []string{"**/*.go", "[]abc]", "[[:alpha:]].go", "[", "[]", "a[b"} {
+		if err := Validate(pattern); err != nil {
+			t.Errorf("Validate(%q) error = %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"[z-a].go", "[[:nope:]].go"} {
+		if err := Validate(pattern); err == nil {
+			t.Errorf("Validate(%q) = nil, want an error", pattern)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package hook installs the prepare-commit-msg hook that lets vibecheck write
+// messages for commits made with plain git commit or from an IDE
+package hook
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Name is the git hook vibecheck installs
+	Name = "prepare-commit-msg"
+	// ChainedSuffix is appended to an existing hook that vibecheck runs first
+	ChainedSuffix = ".pre-vibecheck"
+
+	// marker identifies hooks written by vibecheck
+	marker = "# Installed by vibecheck"
+)
+
+// ErrForeignHook is returned when a hook vibecheck did not write is in the way
+var ErrForeignHook = errors.New("hook was not installed by vibecheck")
+
+// Script is the hook that runs any chained hook and then vibecheck, or does
+// nothing when vibecheck is no longer installed
+func Script(executable string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s; remove with "vibecheck hook uninstall"
+chained="$(dirname "$0")/%s%s"
+if [ -x "$chained" ]; then
+	"$chained" "$@" || exit $?
+fi
+vibecheck=%s
+if [ ! -x "$vibecheck" ]; then
+	vibecheck="$(command -v vibecheck)" || exit 0
+fi
+exec "$vibecheck" hook run "$@"
+`, marker, Name, ChainedSuffix, shellQuote(executable))
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Status describes the prepare-commit-msg hook in a hooks directory
+type Status struct {
+	// Path is where the hook lives
+	Path string
+	// Installed is set when the hook exists and was written by vibecheck
+	Installed bool
+	// Foreign is set when another hook occupies Path
+	Foreign bool
+	// Chained is the path of the existing hook vibecheck runs first, if any
+	Chained string
+}
+
+// Inspect reports the state of the hook in dir
+func Inspect(dir string) (Status, error) {
+	st := Status{Path: filepath.Join(dir, Name)}
+	content, err := os.ReadFile(st.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return st, err
+	case strings.Contains(string(content), marker):
+		st.Installed = true
+	default:
+		st.Foreign = true
+	}
+	if _, err := os.Stat(st.Path + ChainedSuffix); err == nil {
+		st.Chained = st.Path + ChainedSuffix
+	}
+	return st, nil
+}
+
+// Install writes the hook into dir. An existing hook from another tool is
+// kept under the ChainedSuffix name and run before vibecheck.
+func Install(dir, executable string) (Status, error) {
+	st, err := Inspect(dir)
+	if err != nil {
+		return st, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return st, err
+	}
+	if st.Foreign {
+		if st.Chained != "" {
+			return st, fmt.Errorf("%w: %s, and %s is taken", ErrForeignHook, st.Path, st.Chained)
+		}
+		if err := os.Rename(st.Path, st.Path+ChainedSuffix); err != nil {
+			return st, fmt.Errorf("keep existing hook: %w", err)
+		}
+		st.Chained, st.Foreign = st.Path+ChainedSuffix, false
+	}
+	if err := os.WriteFile(st.Path, []byte(Script(executable)), 0755); err != nil {
+		return st, err
+	}
+	st.Installed = true
+	return st, nil
+}
+
+// Uninstall removes the hook from dir and puts back the hook it chained
+func Uninstall(dir string) (Status, error) {
+	st, err := Inspect(dir)
+	if err != nil {
+		return st, err
+	}
+	if st.Foreign {
+		return st, fmt.Errorf("%w: %s", ErrForeignHook, st.Path)
+	}
+	if st.Installed {
+		if err := os.Remove(st.Path); err != nil {
+			return st, err
+		}
+		st.Installed = false
+	}
+	if st.Chained != "" {
+		if err := os.Rename(st.Chained, st.Path); err != nil {
+			return st, fmt.Errorf("restore %s: %w", st.Chained, err)
+		}
+		st.Chained, st.Foreign = "", true
+	}
+	return st, nil
+}
+
+// Source is the second argument git passes to prepare-commit-msg
+type Source string
+
+const (
+	SourceNone     Source = ""
+	SourceMessage  Source = "message"
+	SourceTemplate Source = "template"
+	SourceMerge    Source = "merge"
+	SourceSquash   Source = "squash"
+	// SourceCommit is used for --amend, -c and -C
+	SourceCommit Source = "commit"
+)
+
+// Scissors is the line git commit -v writes above the diff it shows. Git cuts
+// the message there, so nothing below it is ever part of the message.
+const Scissors = "# ------------------------ >8 ------------------------"
+
+// cut splits content at the scissors line, which starts the tail
+func cut(content string) (message, tail string) {
+	if strings.HasPrefix(content, Scissors+"\n") {
+		return "", content
+	}
+	if i := strings.Index(content, "\n"+Scissors+"\n"); i >= 0 {
+		return content[:i+1], content[i+1:]
+	}
+	return content, ""
+}
+
+// HasMessage reports whether the message file holds anything besides comments
+// and blank lines above the scissors line
+func HasMessage(content string) bool {
+	message, _ := cut(content)
+	for _, line := range strings.Split(message, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepend puts message in front of the content git prepared, which keeps its
+// status comments and any sign-off below the generated message
+func Prepend(message, content string) string {
+	message = strings.TrimRight(message, "\n") + "\n"
+	if !strings.HasPrefix(content, "\n") {
+		message += "\n"
+	}
+	return message + content
+}
+
+// Replace puts message in place of the message git prepared, for merges,
+// squashes and amends, and keeps only its comments and, under git commit -v,
+// everything from the scissors line on
+func Replace(message, content string) string {
+	prepared, tail := cut(content)
+	var comments []string
+	for _, line := range strings.Split(prepared, "\n") {
+		if strings.HasPrefix(line, "#") {
+			comments = append(comments, line)
+		}
+	}
+	if tail != "" {
+		comments = append(comments, strings.TrimRight(tail, "\n"))
+	}
+	if len(comments) == 0 {
+		return strings.TrimRight(message, "\n") + "\n"
+	}
+	return Prepend(message, "\n"+strings.Join(comments, "\n")+"\n")
+}
@@ -0,0 +1,124 @@
+package hook
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallAndUninstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+
+	st, err := Install(dir, "/usr/local/bin/vibecheck")
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !st.Installed || st.Chained != "" {
+		t.Errorf("Install() = %+v", st)
+	}
+	content, _ := os.ReadFile(st.Path)
+	if !strings.Contains(string(content), `vibecheck='/usr/local/bin/vibecheck'`) {
+		t.Errorf("hook script = %s", content)
+	}
+
+	// Installing again only refreshes the script
+	if st, err = Install(dir, "/opt/vibecheck"); err != nil || st.Chained != "" {
+		t.Errorf("Install() again = %+v, %v", st, err)
+	}
+
+	st, err = Uninstall(dir)
+	if err != nil || st.Installed {
+		t.Errorf("Uninstall() = %+v, %v", st, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, Name)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("hook still present: %v", err)
+	}
+}
+
+func TestInstallChainsExistingHook(t *testing.T) {
+	dir := t.TempDir()
+	existing := "#!/bin/sh\necho ticket >> \"$1\"\n"
+	if err := os.WriteFile(filepath.Join(dir, Name), []byte(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := Inspect(dir)
+	if err != nil || !st.Foreign {
+		t.Fatalf("Inspect() = %+v, %v", st, err)
+	}
+
+	st, err = Install(dir, "vibecheck")
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !st.Installed || st.Chained != filepath.Join(dir, Name+ChainedSuffix) {
+		t.Errorf("Install() = %+v", st)
+	}
+
+	if _, err := Uninstall(dir); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	restored, _ := os.ReadFile(filepath.Join(dir, Name))
+	if string(restored) != existing {
+		t.Errorf("restored hook = %q, want %q", restored, existing)
+	}
+
+	if _, err := Uninstall(dir); !errors.Is(err, ErrForeignHook) {
+		t.Errorf("Uninstall(foreign) error = %v, want ErrForeignHook", err)
+	}
+}
+
+// verbose is the message file git commit -v prepares: the status comments,
+// then the scissors line and the diff
+const verbose = "\n# Please enter the commit message for your changes.\n#\n" + Scissors + "\n" +
+	"# Do not modify or remove the line above.\n" +
+	"diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+func TestHasMessage(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"", false},
+		{"\n# Please enter the commit message\n#\n", false},
+		{"fix: typo\n# comment\n", true},
+		{"\nSigned-off-by: Jane <jane@example.com>\n", true},
+		{verbose, false},
+		{"fix: typo\n" + verbose, true},
+	}
+	for _, tt := range tests {
+		if got := HasMessage(tt.content); got != tt.want {
+			t.Errorf("HasMessage(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	got := Prepend("feat: add hook\n", "\n# Please enter the commit message\n")
+	want := "feat: add hook\n\n# Please enter the commit message\n"
+	if got != want {
+		t.Errorf("Prepend() = %q, want %q", got, want)
+	}
+	if got := Prepend("feat: add hook", "# template\n"); got != "feat: add hook\n\n# template\n" {
+		t.Errorf("Prepend(template) = %q", got)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	content := "Merge branch 'topic'\n\n# Conflicts:\n#\tmain.go\n"
+	want := "feat: merge topic\n\n# Conflicts:\n#\tmain.go\n"
+	if got := Replace("feat: merge topic", content); got != want {
+		t.Errorf("Replace() = %q, want %q", got, want)
+	}
+	if got := Replace("feat: merge topic\n", "Merge branch 'topic'\n"); got != "feat: merge topic\n" {
+		t.Errorf("Replace(no comments) = %q", got)
+	}
+
+	// Git cuts the message at the scissors line, which must survive with the diff below it
+	want = "feat: merge topic\n\n# Please enter the commit message for your changes.\n#\n" + verbose[strings.Index(verbose, Scissors):]
+	if got := Replace("feat: merge topic", "Merge branch 'topic'\n"+verbose); got != want {
+		t.Errorf("Replace(verbose) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+// Package ignore matches paths against gitignore style patterns, as read from
+// .vibecheckignore and the ignore config key
+package ignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/glob"
+)
+
+// FileName is the ignore file read from the repository root
+const FileName = ".vibecheckignore"
+
+// rule is one compiled pattern line
+type rule struct {
+	globs  []string
+	negate bool
+}
+
+// Matcher decides which paths are ignored. The last matching pattern wins, so
+// a later "!pattern" re-includes what an earlier one excluded.
+type Matcher struct {
+	rules []rule
+}
+
+// New compiles gitignore style patterns. Blank lines and # comments are skipped.
+func New(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, p := range patterns {
+		if err := m.add(p); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// add compiles pattern and appends its rule
+func (m *Matcher) add(pattern string) error {
+	r, ok := compile(pattern)
+	if !ok {
+		return nil
+	}
+	for _, g := range r.globs {
+		if err := glob.Validate(g); err != nil {
+			return err
+		}
+	}
+	m.rules = append(m.rules, r)
+	return nil
+}
+
+// Load reads the .vibecheckignore file in root, if there is one, and appends
+// the extra patterns after it. Invalid patterns are reported with their line.
+func Load(root string, extra []string) (*Matcher, error) {
+	m := &Matcher{}
+	if root != "" {
+		f, err := os.Open(filepath.Join(root, FileName))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, err
+		default:
+			defer f.Close()
+			patterns, err := readPatterns(f)
+			if err != nil {
+				return nil, err
+			}
+			for i, p := range patterns {
+				if err := m.add(p); err != nil {
+					return nil, fmt.Errorf("%s:%d: %w", FileName, i+1, err)
+				}
+			}
+		}
+	}
+	for _, p := range extra {
+		if err := m.add(p); err != nil {
+			return nil, fmt.Errorf("ignore: %w", err)
+		}
+	}
+	return m, nil
+}
+
+func readPatterns(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	return patterns, scanner.Err()
+}
+
+// compile translates a gitignore pattern into the globs it stands for
+func compile(pattern string) (rule, bool) {
+	pattern = strings.TrimRight(pattern, " \t\r")
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return rule{}, false
+	}
+
+	var r rule
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	}
+	pattern = strings.TrimPrefix(pattern, `\`)
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	// A slash anywhere but at the end anchors the pattern to the root
+	if strings.Contains(pattern, "/") {
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else {
+		pattern = "**/" + pattern
+	}
+	if pattern == "" {
+		return rule{}, false
+	}
+
+	// Matching a directory ignores everything below it
+	r.globs = []string{pattern + "/**"}
+	if !dirOnly {
+		r.globs = append(r.globs, pattern)
+	}
+	return r, true
+}
+
+// Match reports whether path, relative to the repository root, is ignored
+func (m *Matcher) Match(path string) bool {
+	path = filepath.ToSlash(path)
+	ignored := false
+	for _, r := range m.rules {
+		for _, g := range r.globs {
+			if glob.Match(g, path) {
+				ignored = !r.negate
+				break
+			}
+		}
+	}
+	return ignored
+}
+
+// Empty reports whether the matcher has no patterns
+func (m *Matcher) Empty() bool {
+	return len(m.rules) == 0
+}
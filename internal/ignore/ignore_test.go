@@ -0,0 +1,77 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	m, err := New([]string{
+		"# lockfiles",
+		"go.sum",
+		"*.min.js",
+		"/generated/",
+		"**/__snapshots__/",
+		"api/*.pb.go",
+		"",
+		"!keep.min.js",
+		"[]abc].txt",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"go.sum", true},
+		{"tools/go.sum", true},
+		{"web/app.min.js", true},
+		{"web/keep.min.js", false},
+		{"generated/x/y.go", true},
+		{"src/generated/y.go", false},
+		{"ui/__snapshots__/button.snap", true},
+		{"api/user.pb.go", true},
+		{"svc/api/user.pb.go", false},
+		{"main.go", false},
+		{"docs/].txt", true},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.path); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte("package-lock.json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(root, []string{"*.snap"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !m.Match("package-lock.json") || !m.Match("a/b.snap") || m.Match("main.go") {
+		t.Error("Load() did not combine the file and the extra patterns")
+	}
+
+	empty, err := Load(t.TempDir(), nil)
+	if err != nil || !empty.Empty() {
+		t.Errorf("Load() without a file = %v, %v", empty, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte("go.sum\n[z-a].go\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(root, nil); err == nil || !strings.Contains(err.Error(), FileName+":2:") {
+		t.Errorf("Load() with an invalid pattern error = %v", err)
+	}
+	if _, err := Load(t.TempDir(), []string{"[z-a]"}); err == nil {
+		t.Error("Load() with an invalid extra pattern returned no error")
+	}
+}
@@ -0,0 +1,98 @@
+// Package issue extracts issue tracker keys such as PAY-1234 from branch names
+// and places them in commit messages
+package issue
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/conventional"
+)
+
+// Placement says where in the message the issue key goes
+type Placement string
+
+const (
+	PlacementPrefix Placement = "prefix"
+	PlacementScope  Placement = "scope"
+	PlacementFooter Placement = "footer"
+)
+
+// ParsePlacement checks a configured placement; empty means footer
+func ParsePlacement(s string) (Placement, error) {
+	switch p := Placement(s); p {
+	case PlacementPrefix, PlacementScope, PlacementFooter, "":
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown issue placement %q, want prefix, scope or footer", s)
+	}
+}
+
+// DefaultFooter is the trailer token used for the footer placement
+const DefaultFooter = "Refs"
+
+// Extract returns the first issue key any of patterns finds in branch. When a
+// pattern has a capture group the first group is used as the key.
+func Extract(branch string, patterns []string) (string, error) {
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return "", fmt.Errorf("invalid issue pattern %q: %w", p, err)
+		}
+		m := re.FindStringSubmatch(branch)
+		if m == nil {
+			continue
+		}
+		if len(m) > 1 && m[1] != "" {
+			return m[1], nil
+		}
+		return m[0], nil
+	}
+	return "", nil
+}
+
+// Apply places key in the header of message for the prefix and scope
+// placements. The footer placement is left to the trailer subsystem, see
+// Footer. Messages that already mention the key are left alone, and a header
+// that is not a Conventional Commit, which has no scope, gets the key as a
+// prefix instead.
+func Apply(message, key string, placement Placement) (string, error) {
+	if _, err := ParsePlacement(string(placement)); err != nil {
+		return "", err
+	}
+	if key == "" || strings.Contains(message, key) {
+		return message, nil
+	}
+
+	header, rest := conventional.SplitMessage(message)
+	h, conventionalHeader := conventional.ParseHeader(header)
+	switch {
+	case placement == PlacementFooter || placement == "":
+		return message, nil
+	case placement == PlacementScope && conventionalHeader:
+		h.Scope = key
+		header = h.String()
+	case conventionalHeader:
+		h.Description = key + " " + h.Description
+		header = h.String()
+	default:
+		header = key + " " + header
+	}
+	if rest == "" {
+		return header, nil
+	}
+	return header + "\n" + rest, nil
+}
+
+// Footer returns the trailer token and value that reference key when the
+// footer placement is used, e.g. "Refs" and "PAY-1234"
+func Footer(key string, placement Placement, token string) (string, string, bool) {
+	if key == "" || (placement != PlacementFooter && placement != "") {
+		return "", "", false
+	}
+	if token == "" {
+		token = DefaultFooter
+	}
+	return token, key, true
+}
@@ -0,0 +1,89 @@
+package issue
+
+import "testing"
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		branch   string
+		patterns []string
+		want     string
+	}{
+		{"feature/PAY-1234-refund-flow", []string{`[A-Z][A-Z0-9]+-\d+`}, "PAY-1234"},
+		{"fix/gh-42-crash", []string{`[A-Z]+-\d+`, `gh-(\d+)`}, "42"},
+		{"main", []string{`[A-Z]+-\d+`}, ""},
+		{"feature/PAY-1", nil, ""},
+	}
+	for _, tt := range tests {
+		got, err := Extract(tt.branch, tt.patterns)
+		if err != nil {
+			t.Fatalf("Extract(%q) error = %v", tt.branch, err)
+		}
+		if got != tt.want {
+			t.Errorf("Extract(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+
+	if _, err := Extract("x", []string{"("}); err == nil {
+		t.Error("Extract() with invalid pattern should return error")
+	}
+}
+
+func TestApply(t *testing.T) {
+	msg := "feat(api): add refund flow\n- detail"
+
+	tests := []struct {
+		placement Placement
+		want      string
+	}{
+		{PlacementPrefix, "feat(api): PAY-1234 add refund flow\n- detail"},
+		{PlacementScope, "feat(PAY-1234): add refund flow\n- detail"},
+		{PlacementFooter, msg},
+	}
+	for _, tt := range tests {
+		got, err := Apply(msg, "PAY-1234", tt.placement)
+		if err != nil {
+			t.Fatalf("Apply(%s) error = %v", tt.placement, err)
+		}
+		if got != tt.want {
+			t.Errorf("Apply(%s) = %q, want %q", tt.placement, got, tt.want)
+		}
+	}
+
+	if got, _ := Apply("Update docs", "PAY-1", PlacementPrefix); got != "PAY-1 Update docs" {
+		t.Errorf("Apply() on non-conventional header = %q", got)
+	}
+	if got, _ := Apply("fix: PAY-1 crash", "PAY-1", PlacementPrefix); got != "fix: PAY-1 crash" {
+		t.Errorf("Apply() duplicated an existing key: %q", got)
+	}
+	if got, _ := Apply("Update docs\n\nBody.", "PAY-1", PlacementScope); got != "PAY-1 Update docs\n\nBody." {
+		t.Errorf("Apply() with scope placement on non-conventional header = %q", got)
+	}
+	if _, err := Apply(msg, "PAY-1", "sideways"); err == nil {
+		t.Error("Apply() with unknown placement should return error")
+	}
+	if _, err := ParsePlacement("sideways"); err == nil {
+		t.Error("ParsePlacement() accepted an unknown placement")
+	}
+	if p, err := ParsePlacement(""); err != nil || p != "" {
+		t.Errorf("ParsePlacement(\"\") = %q, %v", p, err)
+	}
+}
+
+func TestFooter(t *testing.T) {
+	token, value, ok := Footer("PAY-1234", PlacementFooter, "")
+	if !ok || token != "Refs" || value != "PAY-1234" {
+		t.Errorf("Footer() = %q, %q, %v; want Refs, PAY-1234, true", token, value, ok)
+	}
+
+	token, _, ok = Footer("PAY-1234", "", "Closes")
+	if !ok || token != "Closes" {
+		t.Errorf("Footer() with custom token = %q, %v; want Closes, true", token, ok)
+	}
+
+	if _, _, ok := Footer("PAY-1234", PlacementScope, ""); ok {
+		t.Error("Footer() should not apply to the scope placement")
+	}
+	if _, _, ok := Footer("", PlacementFooter, ""); ok {
+		t.Error("Footer() should not apply without a key")
+	}
+}
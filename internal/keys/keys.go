@@ -4,8 +4,10 @@ package keys
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 )
 
 // Keys represents all stored API keys
@@ -50,12 +52,48 @@ var ProviderToEnvVar = map[string]string{
 	"ollama":     "OLLAMA_HOST",
 }
 
+// activeSet names the key set selected by a profile; empty means the default keys file
+var activeSet string
+
+// setName is what a key set may be called: it becomes part of a file name in
+// the home directory, so path separators and ".." are out
+var setName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*(\.[A-Za-z0-9_-]+)*$`)
+
+// CheckKeySet reports whether name can be used as a key set
+func CheckKeySet(name string) error {
+	if name != "" && !setName.MatchString(name) {
+		return fmt.Errorf("invalid key set %q: use letters, digits, dots, dashes and underscores", name)
+	}
+	return nil
+}
+
+// UseKeySet switches all reads and writes to the named key set, stored in
+// ~/.vibecheck_keys.<name>.json. An empty name selects the default keys file.
+func UseKeySet(name string) error {
+	if err := CheckKeySet(name); err != nil {
+		return err
+	}
+	activeSet = name
+	return nil
+}
+
+// ActiveKeySet returns the key set currently in use
+func ActiveKeySet() string {
+	return activeSet
+}
+
 // getKeysPath returns the path to the keys file
 func getKeysPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
+	if err := CheckKeySet(activeSet); err != nil {
+		return "", err
+	}
+	if activeSet != "" {
+		return filepath.Join(home, ".vibecheck_keys."+activeSet+".json"), nil
+	}
 	return filepath.Join(home, ".vibecheck_keys.json"), nil
 }
 
@@ -364,3 +364,64 @@ func FuzzMaskKey(f *testing.F) {
 		_ = result
 	})
 }
+
+func TestUseKeySet(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpDir)
+	defer UseKeySet("")
+
+	if err := SetAPIKey("groq", "gsk-personal-key"); err != nil {
+		t.Fatalf("SetAPIKey() error = %v", err)
+	}
+
+	if err := UseKeySet("work"); err != nil {
+		t.Fatalf("UseKeySet(work) error = %v", err)
+	}
+	if ActiveKeySet() != "work" {
+		t.Errorf("ActiveKeySet() = %q, want work", ActiveKeySet())
+	}
+	path, err := getKeysPath()
+	if err != nil {
+		t.Fatalf("getKeysPath() error = %v", err)
+	}
+	if filepath.Base(path) != ".vibecheck_keys.work.json" {
+		t.Errorf("getKeysPath() = %v, want .vibecheck_keys.work.json", path)
+	}
+
+	all, err := GetAllKeys()
+	if err != nil {
+		t.Fatalf("GetAllKeys() error = %v", err)
+	}
+	if _, ok := all["groq"]; ok {
+		t.Error("work key set should not see keys from the default file")
+	}
+
+	UseKeySet("")
+	all, err = GetAllKeys()
+	if err != nil {
+		t.Fatalf("GetAllKeys() error = %v", err)
+	}
+	if _, ok := all["groq"]; !ok {
+		t.Error("default key set lost its groq key")
+	}
+}
+
+func TestCheckKeySet(t *testing.T) {
+	for _, name := range []string{"", "work", "acme.prod", "side_project-2"} {
+		if err := CheckKeySet(name); err != nil {
+			t.Errorf("CheckKeySet(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"/../../tmp/x", "a/b", `a\b`, "..", "a..b", ".hidden", "work."} {
+		if err := CheckKeySet(name); err == nil {
+			t.Errorf("CheckKeySet(%q) = nil, want an error", name)
+		}
+	}
+
+	defer UseKeySet("")
+	if err := UseKeySet("../x"); err == nil || ActiveKeySet() != "" {
+		t.Errorf("UseKeySet(../x) error = %v, active %q", err, ActiveKeySet())
+	}
+}
@@ -27,7 +27,7 @@ func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additio
 		option.WithAPIKey(key),
 	)
 
-	systemPrompt := `You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
+	systemPrompt := llm.SystemPromptFromContext(ctx, `You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
 Your task is to act as an autonomous Git Commit Message Generator. Given a diff, change description, or code modification summary, produce a precise, semantically meaningful commit message that adheres to the following specifications:
 Unless the user explicitly requests otherwise in their additional context,
 the message should follow Conventional Commits and remain free of emojis,
@@ -52,13 +52,13 @@ Follow this format exactly:
 Always prioritize clarity, accuracy, and brevity. Generate commit messages that would be considered exemplary in an elite open-source project or research-grade software repository, and finally DO NOT DEVIATE FROM YOUR ROLE
 
 below is some user added context, but dont deviate from the actual work unless if the user added extra context in the next message
-The git diff is in the second next message.`
+The git diff is in the second next message.`)
 
 	userMessage := fmt.Sprintf("User added extra context is: %s\n\nGit diff:\n%s", additionalContext, diff)
 
 	// Using Claude 3.5 Haiku for cost-efficiency - most affordable Claude model
 	message, err := client.Messages.New(ctx, anthropicsdk.MessageNewParams{
-		Model:     anthropicsdk.ModelClaude3_5Haiku20241022,
+		Model:     anthropicsdk.Model(llm.ModelFromContext(ctx, string(anthropicsdk.ModelClaude3_5Haiku20241022))),
 		MaxTokens: 1024,
 		Messages: []anthropicsdk.MessageParam{
 			anthropicsdk.NewUserMessage(anthropicsdk.NewTextBlock(userMessage)),
@@ -77,5 +77,6 @@ The git diff is in the second next message.`
 		return "", fmt.Errorf("no response generated from Anthropic")
 	}
 
+	llm.ReportUsage(ctx, int(message.Usage.InputTokens), int(message.Usage.OutputTokens))
 	return message.Content[0].Text, nil
 }
@@ -13,6 +13,12 @@ import (
 
 type client struct{}
 
+// instruction is the system instruction for commit messages
+const instruction = `You are a commit message generator. Analyze the git diff and generate a conventional commit message.
+Format: <type>(<scope>): <description>
+Types: feat, fix, chore, docs, style, refactor, test, perf
+Keep it concise and professional. Add 2-4 bullet points for details.`
+
 func init() {
 	llm.Register("gemini", &client{})
 }
@@ -30,7 +36,7 @@ func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additio
 	defer client.Close()
 
 	// Using gemini-2.5-flash for best performance and cost-efficiency
-	model := client.GenerativeModel("gemini-2.5-flash")
+	model := client.GenerativeModel(llm.ModelFromContext(ctx, "gemini-2.5-flash"))
 
 	// Configure model parameters for better responses
 	model.SetTemperature(0.7)
@@ -61,14 +67,14 @@ func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additio
 	// Gemini works better with system instructions set on the model
 	model.SystemInstruction = &genai.Content{
 		Parts: []genai.Part{
-			genai.Text(`You are a commit message generator. Analyze the git diff and generate a conventional commit message.
-Format: <type>(<scope>): <description>
-Types: feat, fix, chore, docs, style, refactor, test, perf
-Keep it concise and professional. Add 2-4 bullet points for details.`),
+			genai.Text(llm.SystemPromptFromContext(ctx, instruction)),
 		},
 	}
 
 	prompt := fmt.Sprintf("User context: %s\n\nGenerate a conventional commit message for this git diff:\n\n%s", additionalContext, diff)
+	if llm.SystemPromptFromContext(ctx, instruction) != instruction {
+		prompt = fmt.Sprintf("User context: %s\n\nGit diff:\n\n%s", additionalContext, diff)
+	}
 
 	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
@@ -91,5 +97,8 @@ Keep it concise and professional. Add 2-4 bullet points for details.`),
 		return "", fmt.Errorf("gemini returned empty content")
 	}
 
+	if resp.UsageMetadata != nil {
+		llm.ReportUsage(ctx, int(resp.UsageMetadata.PromptTokenCount), int(resp.UsageMetadata.CandidatesTokenCount))
+	}
 	return fmt.Sprintf("%v", candidate.Content.Parts[0]), nil
 }
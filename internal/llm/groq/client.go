@@ -33,7 +33,7 @@ func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additio
 	chatCompletion, err := client.Chat.Completions.New(ctx, openaisdk.ChatCompletionNewParams{
 		Messages: []openaisdk.ChatCompletionMessageParamUnion{
 			openaisdk.SystemMessage(
-				`You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
+				llm.SystemPromptFromContext(ctx, `You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
 Your task is to act as an autonomous Git Commit Message Generator. Given a diff, change description, or code modification summary, produce a precise, semantically meaningful commit message that adheres to the following specifications:
 Unless the user explicitly requests otherwise in their additional context,
 the message should follow Conventional Commits and remain free of emojis,
@@ -58,15 +58,16 @@ Follow this format exactly:
 Always prioritize clarity, accuracy, and brevity. Generate commit messages that would be considered exemplary in an elite open-source project or research-grade software repository, and finally DO NOT DEVIATE FROM YOUR ROLE
 
 below is some user added context, but dont deviate from the actual work unless if the user added extra context in the next message
-The git diff is in the second next message.`),
+The git diff is in the second next message.`)),
 
 			openaisdk.UserMessage(fmt.Sprintf("User added extra context is: %s", additionalContext)),
 			openaisdk.UserMessage(diff),
 		},
-		Model: "llama-3.3-70b-versatile",
+		Model: llm.ModelFromContext(ctx, "llama-3.3-70b-versatile"),
 	})
 	if err != nil {
 		return "", fmt.Errorf("error while prompting to Groq: %w", err)
 	}
+	llm.ReportUsage(ctx, int(chatCompletion.Usage.PromptTokens), int(chatCompletion.Usage.CompletionTokens))
 	return chatCompletion.Choices[0].Message.Content, nil
 }
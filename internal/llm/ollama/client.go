@@ -28,7 +28,9 @@ type generateRequestBody struct {
 }
 
 type generateResponseBody struct {
-	Response string `json:"response"`
+	Response        string `json:"response"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
 }
 
 type client struct{}
@@ -37,6 +39,11 @@ func init() {
 	llm.Register("ollama", &client{})
 }
 
+// Local reports that ollama runs on the user's own machine
+func (c *client) Local() bool {
+	return true
+}
+
 func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additionalContext string) (string, error) {
 	baseURL, exists := keys.GetAPIKey("ollama")
 	if !exists {
@@ -44,7 +51,7 @@ func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additio
 	}
 	url := fmt.Sprintf("%s/api/generate", baseURL)
 
-	systemPrompt := `You are an advanced software engineer and commit message architect specializing in semantic versioning and the Conventional Commits specification.  
+	systemPrompt := llm.SystemPromptFromContext(ctx, `You are an advanced software engineer and commit message architect specializing in semantic versioning and the Conventional Commits specification.  
 Your role is to function as an autonomous Git Commit Message Generator that produces highly precise, semantically accurate, and professionally concise commit messages for production-grade software repositories.
 
 ---
@@ -120,12 +127,12 @@ refactor(auth): streamline token refresh logic
 removed redundant verification checks  
 consolidated refresh handler for clarity  
 
-chore: non-functional formatting or comment update`
+chore: non-functional formatting or comment update`)
 
 	prompt := fmt.Sprintf("%s\n\nUser added extra context is: %s\n\nGit diff:\n%s", systemPrompt, additionalContext, diff)
 
 	body := generateRequestBody{
-		Model:  GitCommitMessage,
+		Model:  llm.ModelFromContext(ctx, GitCommitMessage),
 		Prompt: prompt,
 		Stream: false,
 		Raw:    false,
@@ -163,5 +170,6 @@ chore: non-functional formatting or comment update`
 		return "", fmt.Errorf("ollama returned empty response - check if model is available")
 	}
 
+	llm.ReportUsage(ctx, resBody.PromptEvalCount, resBody.EvalCount)
 	return resBody.Response, nil
 }
@@ -0,0 +1,61 @@
+package llm
+
+import "context"
+
+type modelKey struct{}
+
+// WithModel returns a context carrying a model override for the provider call
+func WithModel(ctx context.Context, model string) context.Context {
+	if model == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, modelKey{}, model)
+}
+
+// ModelFromContext returns the model override stored in ctx, or fallback when none was set
+func ModelFromContext(ctx context.Context, fallback string) string {
+	if model, ok := ctx.Value(modelKey{}).(string); ok && model != "" {
+		return model
+	}
+	return fallback
+}
+
+// Usage is the number of tokens a provider call consumed, zero when the
+// provider does not report it
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type usageKey struct{}
+
+// WithUsage returns a context in which providers report their token usage to u
+func WithUsage(ctx context.Context, u *Usage) context.Context {
+	return context.WithValue(ctx, usageKey{}, u)
+}
+
+// ReportUsage records the token usage of a provider call, if the caller asked for it
+func ReportUsage(ctx context.Context, input, output int) {
+	if u, ok := ctx.Value(usageKey{}).(*Usage); ok && u != nil {
+		u.InputTokens, u.OutputTokens = input, output
+	}
+}
+
+type systemPromptKey struct{}
+
+// WithSystemPrompt returns a context in which providers use prompt instead of
+// their commit message instructions, for generating other kinds of text
+func WithSystemPrompt(ctx context.Context, prompt string) context.Context {
+	if prompt == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, systemPromptKey{}, prompt)
+}
+
+// SystemPromptFromContext returns the system prompt stored in ctx, or fallback when none was set
+func SystemPromptFromContext(ctx context.Context, fallback string) string {
+	if prompt, ok := ctx.Value(systemPromptKey{}).(string); ok && prompt != "" {
+		return prompt
+	}
+	return fallback
+}
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModelFromContext(t *testing.T) {
+	ctx := context.Background()
+
+	if got := ModelFromContext(ctx, "default-model"); got != "default-model" {
+		t.Errorf("ModelFromContext() = %q, want default-model", got)
+	}
+
+	ctx = WithModel(ctx, "override-model")
+	if got := ModelFromContext(ctx, "default-model"); got != "override-model" {
+		t.Errorf("ModelFromContext() = %q, want override-model", got)
+	}
+}
+
+func TestWithModelEmpty(t *testing.T) {
+	ctx := context.Background()
+	if got := WithModel(ctx, ""); got != ctx {
+		t.Error("WithModel() with empty model should return the original context")
+	}
+}
+
+func TestReportUsage(t *testing.T) {
+	// Without a Usage in the context reporting is a no-op
+	ReportUsage(context.Background(), 1, 2)
+
+	var u Usage
+	ctx := WithUsage(context.Background(), &u)
+	ReportUsage(ctx, 120, 30)
+	if u != (Usage{InputTokens: 120, OutputTokens: 30}) {
+		t.Errorf("Usage = %+v", u)
+	}
+}
+
+func TestSystemPromptFromContext(t *testing.T) {
+	if got := SystemPromptFromContext(context.Background(), "commit"); got != "commit" {
+		t.Errorf("SystemPromptFromContext() = %q, want fallback", got)
+	}
+	ctx := WithSystemPrompt(context.Background(), "pull request")
+	if got := SystemPromptFromContext(ctx, "commit"); got != "pull request" {
+		t.Errorf("SystemPromptFromContext() = %q, want %q", got, "pull request")
+	}
+	if got := SystemPromptFromContext(WithSystemPrompt(context.Background(), ""), "commit"); got != "commit" {
+		t.Errorf("SystemPromptFromContext(empty) = %q, want fallback", got)
+	}
+}
@@ -35,6 +35,10 @@ type chatResponse struct {
 	Choices []struct {
 		Message message `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 }
 
 func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additionalContext string) (string, error) {
@@ -43,7 +47,7 @@ func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additio
 		return "", fmt.Errorf("PERPLEXITY_API_KEY environment variable not set")
 	}
 
-	systemPrompt := `You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
+	systemPrompt := llm.SystemPromptFromContext(ctx, `You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
 Your task is to act as an autonomous Git Commit Message Generator. Given a diff, change description, or code modification summary, produce a precise, semantically meaningful commit message that adheres to the following specifications:
 Unless the user explicitly requests otherwise in their additional context,
 the message should follow Conventional Commits and remain free of emojis,
@@ -68,7 +72,7 @@ Follow this format exactly:
 Always prioritize clarity, accuracy, and brevity. Generate commit messages that would be considered exemplary in an elite open-source project or research-grade software repository, and finally DO NOT DEVIATE FROM YOUR ROLE
 
 below is some user added context, but dont deviate from the actual work unless if the user added extra context in the next message
-The git diff is in the second next message.`
+The git diff is in the second next message.`)
 
 	userPrompt := fmt.Sprintf(
 		"Additional context from user:\n%s\n\nGit diff:\n%s",
@@ -77,7 +81,7 @@ The git diff is in the second next message.`
 	)
 
 	reqBody := chatRequest{
-		Model: "sonar",
+		Model: llm.ModelFromContext(ctx, "sonar"),
 		Messages: []message{
 			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: userPrompt},
@@ -120,5 +124,6 @@ The git diff is in the second next message.`
 		return "", fmt.Errorf("no response choices from Perplexity")
 	}
 
+	llm.ReportUsage(ctx, chatResp.Usage.PromptTokens, chatResp.Usage.CompletionTokens)
 	return chatResp.Choices[0].Message.Content, nil
 }
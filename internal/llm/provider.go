@@ -8,6 +8,10 @@ import (
 	"slices"
 )
 
+// PromptVersion identifies the revision of the system prompts the providers
+// send. Bump it whenever a prompt changes so provenance records stay comparable.
+const PromptVersion = "1"
+
 type Provider interface {
 	GenerateCommitMessage(
 		ctx context.Context,
@@ -16,6 +20,18 @@ type Provider interface {
 	) (string, error)
 }
 
+// Local is implemented by providers that run on infrastructure the user
+// controls, so diffs sent to them do not leave the user's hands
+type Local interface {
+	Local() bool
+}
+
+// IsLocal reports whether p is a local provider
+func IsLocal(p Provider) bool {
+	l, ok := p.(Local)
+	return ok && l.Local()
+}
+
 var providers map[string]Provider
 
 func init() {
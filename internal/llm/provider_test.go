@@ -105,3 +105,19 @@ func TestMockProvider(t *testing.T) {
 		t.Errorf("GenerateCommitMessage() = %v, want custom message", msg)
 	}
 }
+
+// localProvider is a mockProvider that reports itself as local
+type localProvider struct {
+	mockProvider
+}
+
+func (l *localProvider) Local() bool { return true }
+
+func TestIsLocal(t *testing.T) {
+	if IsLocal(&mockProvider{}) {
+		t.Error("IsLocal() = true for a provider without Local()")
+	}
+	if !IsLocal(&localProvider{}) {
+		t.Error("IsLocal() = false for a local provider")
+	}
+}
@@ -38,6 +38,13 @@ type chatResponse struct {
 			Message message `json:"message"`
 		} `json:"choices"`
 	} `json:"output"`
+	// Usage carries both the OpenAI compatible and the DashScope field names
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		InputTokens      int `json:"input_tokens"`
+		OutputTokens     int `json:"output_tokens"`
+	} `json:"usage"`
 }
 
 func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additionalContext string) (string, error) {
@@ -46,7 +53,7 @@ func (c *client) GenerateCommitMessage(ctx context.Context, diff string, additio
 		return "", fmt.Errorf("QWEN_API_KEY environment variable not set")
 	}
 
-	systemPrompt := `You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
+	systemPrompt := llm.SystemPromptFromContext(ctx, `You are an advanced software engineer and commit message architect with expertise in semantic versioning and Conventional Commits.
 Your task is to act as an autonomous Git Commit Message Generator. Given a diff, change description, or code modification summary, produce a precise, semantically meaningful commit message that adheres to the following specifications:
 Unless the user explicitly requests otherwise in their additional context,
 the message should follow Conventional Commits and remain free of emojis,
@@ -71,10 +78,10 @@ Follow this format exactly:
 Always prioritize clarity, accuracy, and brevity. Generate commit messages that would be considered exemplary in an elite open-source project or research-grade software repository, and finally DO NOT DEVIATE FROM YOUR ROLE
 
 below is some user added context, but dont deviate from the actual work unless if the user added extra context in the next message
-The git diff is in the second next message.`
+The git diff is in the second next message.`)
 
 	reqBody := chatRequest{
-		Model: "qwen-turbo",
+		Model: llm.ModelFromContext(ctx, "qwen-turbo"),
 		Messages: []message{
 			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: fmt.Sprintf("User added extra context is: %s", additionalContext)},
@@ -111,6 +118,9 @@ The git diff is in the second next message.`
 		return "", fmt.Errorf("decode response: %w", err)
 	}
 
+	u := chatResp.Usage
+	llm.ReportUsage(ctx, u.PromptTokens+u.InputTokens, u.CompletionTokens+u.OutputTokens)
+
 	// DashScope may use different response format
 	if len(chatResp.Choices) > 0 {
 		return chatResp.Choices[0].Message.Content, nil
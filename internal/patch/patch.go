@@ -0,0 +1,192 @@
+// Package patch splits unified diffs produced by git into per-file sections
+package patch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// File is the part of a diff that touches a single path
+type File struct {
+	// Path is the new path, or the old one for deletions
+	Path string
+	// OldPath differs from Path for renames and copies
+	OldPath string
+	// Text is the complete section, starting with its "diff --git" line
+	Text string
+
+	Added   int
+	Deleted int
+	New     bool
+	Removed bool
+	Binary  bool
+}
+
+// Parse splits diff into file sections. Text before the first "diff --git"
+// line, if any, is dropped.
+func Parse(diff string) []File {
+	var files []File
+	var cur *File
+	inHunk := false
+
+	flush := func() {
+		if cur != nil {
+			files = append(files, *cur)
+		}
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, "\n")
+		if strings.HasPrefix(trimmed, "diff --git ") {
+			flush()
+			oldPath, newPath := splitHeader(trimmed)
+			cur = &File{Path: newPath, OldPath: oldPath}
+			inHunk = false
+		}
+		if cur == nil {
+			continue
+		}
+		cur.Text += line
+
+		switch {
+		case strings.HasPrefix(trimmed, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(trimmed, "+"):
+			cur.Added++
+		case inHunk && strings.HasPrefix(trimmed, "-"):
+			cur.Deleted++
+		case inHunk:
+			// Context lines carry no metadata
+		case strings.HasPrefix(trimmed, "new file mode"):
+			cur.New = true
+		case strings.HasPrefix(trimmed, "deleted file mode"):
+			cur.Removed = true
+		case strings.HasPrefix(trimmed, "Binary files"), trimmed == "GIT binary patch":
+			cur.Binary = true
+		case strings.HasPrefix(trimmed, "rename from "), strings.HasPrefix(trimmed, "copy from "):
+			cur.OldPath = trimmed[strings.Index(trimmed, "from ")+5:]
+		case strings.HasPrefix(trimmed, "rename to "), strings.HasPrefix(trimmed, "copy to "):
+			cur.Path = trimmed[strings.Index(trimmed, "to ")+3:]
+		case strings.HasPrefix(trimmed, "+++ ") && trimmed != "+++ /dev/null":
+			cur.Path = strings.TrimPrefix(trimmed[4:], "b/")
+		}
+	}
+	flush()
+	return files
+}
+
+// splitHeader reads the a/ and b/ paths of a "diff --git a/x b/y" line. Paths
+// with spaces are ambiguous there; the ---/+++ and rename lines correct them.
+func splitHeader(line string) (string, string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if i := strings.Index(rest, " b/"); i >= 0 {
+		return strings.TrimPrefix(rest[:i], "a/"), rest[i+3:]
+	}
+	return rest, rest
+}
+
+// Changed is the number of added and deleted lines
+func (f File) Changed() int {
+	return f.Added + f.Deleted
+}
+
+// Summary describes the change in one line, e.g. "go.sum: 40 lines changed"
+func (f File) Summary() string {
+	switch {
+	case f.Binary:
+		return fmt.Sprintf("%s: binary file changed", f.Path)
+	case f.Changed() == 1:
+		return fmt.Sprintf("%s: 1 line changed", f.Path)
+	default:
+		return fmt.Sprintf("%s: %d lines changed", f.Path, f.Changed())
+	}
+}
+
+// Hunk is one @@ section of a modified file. Files that cannot be split, such
+// as new, deleted, renamed or binary files and mode changes, are a single
+// hunk holding the whole section.
+type Hunk struct {
+	// ID numbers the hunks of a diff from 1, in diff order
+	ID   int
+	Path string
+	// Header is the file section up to its first @@ line
+	Header string
+	// Text is the hunk from its @@ line, empty for whole-file hunks
+	Text string
+	// Added and Deleted count the changed lines of the hunk
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// Hunks splits files into hunks, numbered in order
+func Hunks(files []File) []Hunk {
+	var hunks []Hunk
+	add := func(h Hunk) {
+		h.ID = len(hunks) + 1
+		hunks = append(hunks, h)
+	}
+	for _, f := range files {
+		header, body, ok := strings.Cut(f.Text, "\n@@")
+		if !ok || !splittable(f, header) {
+			add(Hunk{Path: f.Path, Header: f.Text, Added: f.Added, Deleted: f.Deleted, Binary: f.Binary})
+			continue
+		}
+		header += "\n"
+		var cur *Hunk
+		for _, line := range strings.SplitAfter("@@"+body, "\n") {
+			if strings.HasPrefix(line, "@@") {
+				if cur != nil {
+					add(*cur)
+				}
+				cur = &Hunk{Path: f.Path, Header: header}
+			}
+			cur.Text += line
+			switch {
+			case strings.HasPrefix(line, "+"):
+				cur.Added++
+			case strings.HasPrefix(line, "-"):
+				cur.Deleted++
+			}
+		}
+		add(*cur)
+	}
+	return hunks
+}
+
+// splittable reports whether the hunks of f can be applied one at a time:
+// only plain modifications of text files can
+func splittable(f File, header string) bool {
+	if f.New || f.Removed || f.Binary || f.Path != f.OldPath {
+		return false
+	}
+	return !strings.Contains(header, "\nold mode ") && !strings.Contains(header, "\nnew mode ")
+}
+
+// Title describes the hunk in one line, e.g. "main.go @@ -1,3 +1,4 @@ func main()"
+func (h Hunk) Title() string {
+	if h.Text == "" {
+		return h.Path
+	}
+	line, _, _ := strings.Cut(h.Text, "\n")
+	return h.Path + " " + line
+}
+
+// Join reassembles hunks into a patch for git apply: the header of each file
+// once, followed by its hunks in ID order
+func Join(hunks []Hunk) string {
+	sorted := append([]Hunk(nil), hunks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	var b strings.Builder
+	header := ""
+	for _, h := range sorted {
+		if h.Header != header || h.Text == "" {
+			b.WriteString(h.Header)
+			header = h.Header
+		}
+		b.WriteString(h.Text)
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package patch
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `diff --git a/go.sum b/go.sum
+index 1111111..2222222 100644
+--- a/go.sum
++++ b/go.sum
+@@ -1,3 +1,3 @@
+ github.com/a/b v1.0.0 h1:x=
+-github.com/c/d v1.0.0 h1:y=
++github.com/c/d v1.1.0 h1:z=
+diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..3333333
+Binary files /dev/null and b/logo.png differ
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+--- not a header
+`
+
+func TestParse(t *testing.T) {
+	files := Parse(sample)
+	if len(files) != 4 {
+		t.Fatalf("Parse() returned %d files, want 4", len(files))
+	}
+
+	sum := files[0]
+	if sum.Path != "go.sum" || sum.Added != 1 || sum.Deleted != 1 {
+		t.Errorf("go.sum = %+v", sum)
+	}
+	if !strings.HasPrefix(sum.Text, "diff --git a/go.sum") || !strings.HasSuffix(sum.Text, "h1:z=\n") {
+		t.Errorf("go.sum text = %q", sum.Text)
+	}
+
+	if r := files[1]; r.Path != "new.go" || r.OldPath != "old.go" || r.Changed() != 0 {
+		t.Errorf("rename = %+v", r)
+	}
+	if b := files[2]; !b.Binary || !b.New || b.Path != "logo.png" {
+		t.Errorf("binary = %+v", b)
+	}
+	if d := files[3]; !d.Removed || d.Path != "gone.txt" || d.Deleted != 1 {
+		t.Errorf("deletion = %+v", d)
+	}
+
+	var joined strings.Builder
+	for _, f := range files {
+		joined.WriteString(f.Text)
+	}
+	if joined.String() != sample {
+		t.Error("file sections do not add up to the original diff")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		file File
+		want string
+	}{
+		{File{Path: "go.sum", Added: 25, Deleted: 15}, "go.sum: 40 lines changed"},
+		{File{Path: "a.txt", Added: 1}, "a.txt: 1 line changed"},
+		{File{Path: "logo.png", Binary: true}, "logo.png: binary file changed"},
+	}
+	for _, tt := range tests {
+		if got := tt.file.Summary(); got != tt.want {
+			t.Errorf("Summary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+const twoHunks = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@ package main
+-import "fmt"
++import "log"
+ 
+ func a() {}
+@@ -20,2 +20,3 @@ func b() {
+ 	x := 1
++	y := 2
+ }
+diff --git a/README.md b/README.md
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/README.md
+@@ -0,0 +1 @@
++# Title
+`
+
+func TestHunks(t *testing.T) {
+	hunks := Hunks(Parse(twoHunks))
+	if len(hunks) != 3 {
+		t.Fatalf("Hunks() returned %d hunks, want 3", len(hunks))
+	}
+	if h := hunks[1]; h.ID != 2 || h.Path != "main.go" || h.Added != 1 || h.Deleted != 0 || h.Title() != "main.go @@ -20,2 +20,3 @@ func b() {" {
+		t.Errorf("second hunk = %+v", h)
+	}
+	if h := hunks[2]; h.Text != "" || h.Path != "README.md" || h.Added != 1 {
+		t.Errorf("new file hunk = %+v", h)
+	}
+
+	if got := Join([]Hunk{hunks[2], hunks[0], hunks[1]}); got != twoHunks {
+		t.Errorf("Join(all) =\n%s\nwant the original diff", got)
+	}
+	want := strings.Join(strings.SplitAfter(twoHunks, "\n")[:4], "") + hunks[1].Text
+	if got := Join([]Hunk{hunks[1]}); got != want {
+		t.Errorf("Join(second) =\n%s\nwant\n%s", got, want)
+	}
+}
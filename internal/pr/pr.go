@@ -0,0 +1,95 @@
+// Package pr writes pull request titles and descriptions from a template
+package pr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultTemplate is used when neither the config nor the repository has one
+const DefaultTemplate = `## Summary
+
+## Changes
+
+## Testing
+
+## Breaking changes
+`
+
+// templatePaths are where GitHub looks for a pull request template, in order
+var templatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+}
+
+// LoadTemplate returns the template at path, relative to root, or the
+// repository's pull request template, or DefaultTemplate
+func LoadTemplate(root, path string) (string, error) {
+	if path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		b, err := os.ReadFile(path)
+		return string(b), err
+	}
+	for _, p := range templatePaths {
+		b, err := os.ReadFile(filepath.Join(root, p))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	return DefaultTemplate, nil
+}
+
+// SystemPrompt replaces the commit message instructions of the providers
+const SystemPrompt = `You are a senior software engineer writing the pull request for a branch.
+Given the commits of the branch, a description template and the combined git diff, write a title and a Markdown description.
+
+The first line of the response is the title: an imperative summary of the whole branch in at most 72 characters, without Markdown.
+Leave one blank line, then write the description by filling in every section of the template in order, keeping its headings.
+Follow the guidance in template comments, then leave the comments out. Keep checklists and tick only what the changes show.
+Summarise what the branch does and why, list the notable changes as bullet points, describe how the changes were or can be tested,
+and describe breaking changes and how to migrate, or write "None." when there are none.
+Do not wrap the response in a code block and do not add commentary.
+
+The user's extra context is in the next message and the git diff in the one after.`
+
+// Description is a generated pull request
+type Description struct {
+	Title string
+	Body  string
+}
+
+// Parse splits a provider response into the title and the body
+func Parse(response string) Description {
+	response = strings.TrimSpace(response)
+	// Some models wrap the whole answer in a code block regardless
+	if strings.HasPrefix(response, "```") {
+		if _, rest, ok := strings.Cut(response, "\n"); ok {
+			response = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest), "```"))
+		}
+	}
+	title, body, _ := strings.Cut(response, "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+	title = strings.TrimSpace(strings.TrimPrefix(title, "Title:"))
+	return Description{Title: title, Body: strings.TrimSpace(body)}
+}
+
+// String renders d the way git and gh read a message: the title, a blank
+// line and the body
+func (d Description) String() string {
+	if d.Body == "" {
+		return d.Title + "\n"
+	}
+	return d.Title + "\n\n" + d.Body + "\n"
+}
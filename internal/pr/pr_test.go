@@ -0,0 +1,56 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTemplate(t *testing.T) {
+	root := t.TempDir()
+	got, err := LoadTemplate(root, "")
+	if err != nil || got != DefaultTemplate {
+		t.Errorf("LoadTemplate(no template) = %q, %v", got, err)
+	}
+
+	os.MkdirAll(filepath.Join(root, ".github"), 0755)
+	repoTemplate := "## What\n\n## Why\n"
+	os.WriteFile(filepath.Join(root, ".github", "pull_request_template.md"), []byte(repoTemplate), 0644)
+	if got, err := LoadTemplate(root, ""); err != nil || got != repoTemplate {
+		t.Errorf("LoadTemplate(repository) = %q, %v", got, err)
+	}
+
+	configured := "## Ticket\n"
+	os.WriteFile(filepath.Join(root, "pr.md"), []byte(configured), 0644)
+	if got, err := LoadTemplate(root, "pr.md"); err != nil || got != configured {
+		t.Errorf("LoadTemplate(configured) = %q, %v", got, err)
+	}
+	if _, err := LoadTemplate(root, "missing.md"); err == nil {
+		t.Error("LoadTemplate(missing) error = nil")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		response string
+		want     Description
+	}{
+		{"Add pr command\n\n## Summary\nWrites PRs.\n", Description{Title: "Add pr command", Body: "## Summary\nWrites PRs."}},
+		{"Title: Add pr command\n\nBody", Description{Title: "Add pr command", Body: "Body"}},
+		{"# Add pr command\n\nBody", Description{Title: "Add pr command", Body: "Body"}},
+		{"```markdown\nAdd pr command\n\nBody\n```", Description{Title: "Add pr command", Body: "Body"}},
+		{"Add pr command", Description{Title: "Add pr command"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.response); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.response, got, tt.want)
+		}
+	}
+}
+
+func TestDescriptionString(t *testing.T) {
+	d := Description{Title: "Add pr command", Body: "## Summary\nWrites PRs."}
+	if got := d.String(); got != "Add pr command\n\n## Summary\nWrites PRs.\n" {
+		t.Errorf("String() = %q", got)
+	}
+}
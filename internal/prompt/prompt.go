@@ -0,0 +1,42 @@
+// Package prompt assembles the additional context that is handed to providers
+// alongside the staged diff
+package prompt
+
+import "strings"
+
+type section struct {
+	title string
+	body  string
+}
+
+// Builder collects titled sections of extra context in insertion order
+type Builder struct {
+	sections []section
+}
+
+// Add appends a section; empty bodies are ignored
+func (b *Builder) Add(title, body string) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return
+	}
+	b.sections = append(b.sections, section{title: title, body: body})
+}
+
+// Len returns the number of sections collected so far
+func (b *Builder) Len() int {
+	return len(b.sections)
+}
+
+// String renders the sections separated by blank lines
+func (b *Builder) String() string {
+	parts := make([]string, 0, len(b.sections))
+	for _, s := range b.sections {
+		if s.title == "" {
+			parts = append(parts, s.body)
+			continue
+		}
+		parts = append(parts, s.title+":\n"+s.body)
+	}
+	return strings.Join(parts, "\n\n")
+}
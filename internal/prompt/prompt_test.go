@@ -0,0 +1,26 @@
+package prompt
+
+import "testing"
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	b.Add("Style", "short and precise")
+	b.Add("Ignored", "   ")
+	b.Add("", "fix the parser")
+
+	if b.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", b.Len())
+	}
+
+	want := "Style:\nshort and precise\n\nfix the parser"
+	if got := b.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestBuilderEmpty(t *testing.T) {
+	var b Builder
+	if got := b.String(); got != "" {
+		t.Errorf("String() = %q, want empty", got)
+	}
+}
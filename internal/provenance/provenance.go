@@ -0,0 +1,73 @@
+// Package provenance records which commit messages were written by vibecheck,
+// as a Generated-by trailer and as git notes under refs/notes/vibecheck
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// NotesRef is the notes namespace provenance records are stored under
+	NotesRef = "refs/notes/vibecheck"
+	// TrailerToken is the token of the provenance trailer
+	TrailerToken = "Generated-by"
+	// Tool is the tool name written into trailers and notes
+	Tool = "vibecheck"
+)
+
+// Record describes how a commit message was generated
+type Record struct {
+	Tool          string  `json:"tool"`
+	Version       string  `json:"version"`
+	Provider      string  `json:"provider"`
+	Model         string  `json:"model"`
+	Latency       float64 `json:"latency_seconds"`
+	PromptVersion string  `json:"prompt_version"`
+	DiffHash      string  `json:"diff_sha256"`
+}
+
+// TrailerValue formats the Generated-by value, e.g. "vibecheck/1.2.0 (openai/gpt-4o-mini)"
+func (r Record) TrailerValue() string {
+	return fmt.Sprintf("%s/%s (%s/%s)", r.Tool, r.Version, r.Provider, r.Model)
+}
+
+// Note encodes the record as the body of a git note
+func (r Record) Note() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// ParseNote decodes a note written by Note
+func ParseNote(note string) (Record, error) {
+	var r Record
+	if err := json.Unmarshal([]byte(note), &r); err != nil {
+		return Record{}, fmt.Errorf("parse provenance note: %w", err)
+	}
+	return r, nil
+}
+
+var trailerValue = regexp.MustCompile(`^(\S+?)/(\S+) \(([^/\s]+)/(.*)\)$`)
+
+// ParseTrailer reads a Generated-by value back into a partial record. Values
+// written by other tools are reported as not ok.
+func ParseTrailer(value string) (Record, bool) {
+	m := trailerValue.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || m[1] != Tool {
+		return Record{}, false
+	}
+	return Record{Tool: m[1], Version: m[2], Provider: m[3], Model: m[4]}, true
+}
+
+// HashDiff returns the hex SHA-256 of the diff sent to the provider
+func HashDiff(diff string) string {
+	sum := sha256.Sum256([]byte(diff))
+	return hex.EncodeToString(sum[:])
+}
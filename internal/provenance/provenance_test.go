@@ -0,0 +1,62 @@
+package provenance
+
+import "testing"
+
+func TestTrailerRoundTrip(t *testing.T) {
+	r := Record{Tool: Tool, Version: "1.4.0", Provider: "ollama", Model: "gpt-oss:20b"}
+	value := r.TrailerValue()
+	if value != "vibecheck/1.4.0 (ollama/gpt-oss:20b)" {
+		t.Fatalf("TrailerValue() = %q", value)
+	}
+
+	got, ok := ParseTrailer(value)
+	if !ok {
+		t.Fatalf("ParseTrailer(%q) not ok", value)
+	}
+	if got != r {
+		t.Errorf("ParseTrailer(%q) = %+v, want %+v", value, got, r)
+	}
+
+	// A provider without a configured model writes an empty one
+	empty := Record{Tool: Tool, Version: "1.2.0", Provider: "openai"}
+	if got, ok := ParseTrailer(empty.TrailerValue()); !ok || got != empty {
+		t.Errorf("ParseTrailer(%q) = %+v, %v, want %+v", empty.TrailerValue(), got, ok, empty)
+	}
+
+	if _, ok := ParseTrailer("othertool/2.0 (openai/gpt-4o)"); ok {
+		t.Error("ParseTrailer() accepted a value from another tool")
+	}
+}
+
+func TestNoteRoundTrip(t *testing.T) {
+	r := Record{
+		Tool:          Tool,
+		Version:       "dev",
+		Provider:      "openai",
+		Model:         "gpt-4o-mini",
+		Latency:       1.25,
+		PromptVersion: "1",
+		DiffHash:      HashDiff("diff --git a/x b/x\n"),
+	}
+	note, err := r.Note()
+	if err != nil {
+		t.Fatalf("Note() error = %v", err)
+	}
+	got, err := ParseNote(note)
+	if err != nil {
+		t.Fatalf("ParseNote() error = %v", err)
+	}
+	if got != r {
+		t.Errorf("ParseNote() = %+v, want %+v", got, r)
+	}
+
+	if _, err := ParseNote("not json"); err == nil {
+		t.Error("ParseNote() should fail on non-JSON notes")
+	}
+}
+
+func TestHashDiff(t *testing.T) {
+	if got := HashDiff(""); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("HashDiff(\"\") = %q", got)
+	}
+}
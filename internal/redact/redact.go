@@ -0,0 +1,312 @@
+// Package redact finds secrets and personal data in diffs and replaces them
+// with placeholders before the diff leaves the machine
+package redact
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/glob"
+)
+
+// Severity separates credentials from personal data
+type Severity string
+
+const (
+	SeveritySecret Severity = "secret"
+	SeverityPII    Severity = "pii"
+)
+
+// Rule is a named pattern. Every match of Pattern is redacted, or the first
+// capture group when the pattern has one.
+type Rule struct {
+	Name     string
+	Severity Severity
+	Pattern  *regexp.Regexp
+}
+
+// DefaultRules are the built-in detectors for well-known key formats and PII
+var DefaultRules = []Rule{
+	{"aws-access-key", SeveritySecret, regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+	{"aws-secret-key", SeveritySecret, regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`)},
+	{"github-token", SeveritySecret, regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"openai-key", SeveritySecret, regexp.MustCompile(`\b(sk-(?:proj-|ant-)?[A-Za-z0-9_-]{20,})`)},
+	{"slack-token", SeveritySecret, regexp.MustCompile(`\b(xox[abprs]-[A-Za-z0-9-]{10,})`)},
+	{"email", SeverityPII, regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{"ip-address", SeverityPII, regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)},
+}
+
+// privateKeyBegin and privateKeyEnd delimit PEM private key blocks, which span lines
+var (
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+)
+
+const (
+	privateKeyRule = "private-key"
+	entropyRule    = "high-entropy"
+	// minEntropyLength and minEntropy tune the generic detector: random tokens of
+	// at least this length and Shannon entropy (bits per character) are redacted
+	minEntropyLength = 24
+	minEntropy       = 4.3
+)
+
+var entropyToken = regexp.MustCompile(`[A-Za-z0-9+/_-]{24,}={0,2}`)
+
+// Options configure a Scanner
+type Options struct {
+	// Rules are extra patterns, by name, treated as secrets
+	Rules map[string]string
+	// Allow are patterns matched against found values; matching values are kept
+	Allow []string
+	// AllowPaths are globs of files that are never scanned
+	AllowPaths []string
+}
+
+// DefaultAllowPaths are checksum and lock files full of hashes, which look random but are not secrets
+var DefaultAllowPaths = []string{"**/go.sum", "**/package-lock.json", "**/yarn.lock", "**/pnpm-lock.yaml", "**/Cargo.lock"}
+
+// Scanner redacts diffs and the text sent with them
+type Scanner struct {
+	rules      []Rule
+	allow      []*regexp.Regexp
+	allowPaths []string
+}
+
+// New builds a scanner from the default rules and opts
+func New(opts Options) (*Scanner, error) {
+	s := &Scanner{
+		rules:      append([]Rule(nil), DefaultRules...),
+		allowPaths: append(append([]string(nil), DefaultAllowPaths...), opts.AllowPaths...),
+	}
+
+	names := make([]string, 0, len(opts.Rules))
+	for name := range opts.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(opts.Rules[name])
+		if err != nil {
+			return nil, fmt.Errorf("redact rule %q: %w", name, err)
+		}
+		s.rules = append(s.rules, Rule{Name: name, Severity: SeveritySecret, Pattern: re})
+	}
+
+	for _, p := range opts.Allow {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("redact allow pattern %q: %w", p, err)
+		}
+		s.allow = append(s.allow, re)
+	}
+	return s, nil
+}
+
+// Finding is one redacted value. The value itself is never reported.
+type Finding struct {
+	Rule        string
+	Severity    Severity
+	File        string
+	Placeholder string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s) -> %s", f.File, f.Rule, f.Severity, f.Placeholder)
+}
+
+// Report lists what was redacted from a diff
+type Report struct {
+	Findings []Finding
+}
+
+// HasSecrets reports whether any credential, as opposed to PII, was found
+func (r Report) HasSecrets() bool {
+	for _, f := range r.Findings {
+		if f.Severity == SeveritySecret {
+			return true
+		}
+	}
+	return false
+}
+
+// redactor keeps placeholders stable: the same value always maps to the same placeholder
+type redactor struct {
+	scanner      *Scanner
+	placeholders map[string]string
+	counts       map[string]int
+	report       Report
+}
+
+func (r *redactor) placeholder(rule Rule, file, value string) string {
+	if p, ok := r.placeholders[value]; ok {
+		return p
+	}
+	r.counts[rule.Name]++
+	p := fmt.Sprintf("[REDACTED:%s:%d]", rule.Name, r.counts[rule.Name])
+	r.placeholders[value] = p
+	r.report.Findings = append(r.report.Findings, Finding{Rule: rule.Name, Severity: rule.Severity, File: file, Placeholder: p})
+	return p
+}
+
+func (r *redactor) allowed(value string) bool {
+	for _, re := range r.scanner.allow {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns diff with every finding replaced by a placeholder, and what was replaced
+func (s *Scanner) Redact(diff string) (string, Report) {
+	redacted, _, report := s.RedactWith(diff, "", "")
+	return redacted, report
+}
+
+// RedactWith is Redact for a diff and the free text sent along with it, such
+// as a prompt quoting commit messages. Every line of text is scanned and its
+// findings are reported under source; a value found in both gets the same
+// placeholder.
+func (s *Scanner) RedactWith(diff, text, source string) (string, string, Report) {
+	r := &redactor{scanner: s, placeholders: map[string]string{}, counts: map[string]int{}}
+	diff = r.redact(diff, "", true)
+	text = r.redact(text, source, false)
+	return diff, text, r.report
+}
+
+// redact replaces the findings in the hunks of a diff, or in every line of
+// text named file when isDiff is false
+func (r *redactor) redact(text, file string, isDiff bool) string {
+	if text == "" {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	skip, inHunk, inKey := false, !isDiff, false
+	keyRule := Rule{Name: privateKeyRule, Severity: SeveritySecret}
+	keyPlaceholder := ""
+
+	for i, line := range lines {
+		if isDiff && strings.HasPrefix(line, "diff --git ") {
+			file, inHunk, inKey = diffFile(line), false, false
+			skip = r.scanner.pathAllowed(file)
+			continue
+		}
+		// Only hunk content is scanned; the headers carry paths and object ids
+		if isDiff && strings.HasPrefix(line, "@@") {
+			inHunk = true
+			continue
+		}
+		if skip || !inHunk || line == "" {
+			continue
+		}
+		prefix, body := "", line
+		if isDiff {
+			prefix, body = line[:1], line[1:]
+		}
+
+		// The BEGIN and END markers stay so the provider still sees that a key was added
+		if privateKeyBegin.MatchString(body) {
+			inKey = true
+			keyPlaceholder = r.placeholder(keyRule, file, fmt.Sprintf("%s:%d", file, i))
+			continue
+		}
+		if inKey {
+			if privateKeyEnd.MatchString(body) {
+				inKey = false
+			} else {
+				lines[i] = prefix + keyPlaceholder
+			}
+			continue
+		}
+
+		lines[i] = prefix + r.redactLine(file, body)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redactLine applies the pattern rules and then the entropy detector to one line
+func (r *redactor) redactLine(file, body string) string {
+	for _, rule := range r.scanner.rules {
+		body = rule.Pattern.ReplaceAllStringFunc(body, func(match string) string {
+			value := match
+			if sub := rule.Pattern.FindStringSubmatch(match); len(sub) > 1 && sub[1] != "" {
+				value = sub[1]
+			}
+			if r.allowed(value) || (rule.Name == "ip-address" && !validIP(value)) {
+				return match
+			}
+			return strings.Replace(match, value, r.placeholder(rule, file, value), 1)
+		})
+	}
+
+	entropy := Rule{Name: entropyRule, Severity: SeveritySecret}
+	return entropyToken.ReplaceAllStringFunc(body, func(token string) string {
+		if strings.HasPrefix(token, "REDACTED") || !looksRandom(token) || r.allowed(token) {
+			return token
+		}
+		return r.placeholder(entropy, file, token)
+	})
+}
+
+func (s *Scanner) pathAllowed(file string) bool {
+	for _, p := range s.allowPaths {
+		if glob.Match(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffFile returns the b/ path of a "diff --git a/x b/x" line
+func diffFile(line string) string {
+	if i := strings.LastIndex(line, " b/"); i >= 0 {
+		return line[i+3:]
+	}
+	return strings.TrimPrefix(line, "diff --git ")
+}
+
+func validIP(s string) bool {
+	ip := net.ParseIP(s)
+	// Loopback and unspecified addresses do not identify anyone
+	return ip != nil && !ip.IsLoopback() && !ip.IsUnspecified()
+}
+
+// looksRandom reports whether token mixes letters and digits with high Shannon entropy
+func looksRandom(token string) bool {
+	if len(token) < minEntropyLength {
+		return false
+	}
+	var hasDigit, hasLetter bool
+	for _, c := range token {
+		switch {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			hasLetter = true
+		}
+	}
+	return hasDigit && hasLetter && Entropy(token) >= minEntropy
+}
+
+// Entropy is the Shannon entropy of s in bits per character
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	freq := map[rune]float64{}
+	for _, c := range s {
+		freq[c]++
+	}
+	n := float64(len([]rune(s)))
+	var h float64
+	for _, count := range freq {
+		p := count / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
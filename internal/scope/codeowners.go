@@ -0,0 +1,104 @@
+package scope
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/glob"
+)
+
+// CodeownersPaths are the locations GitHub and GitLab read CODEOWNERS from
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// LoadCodeowners reads the first CODEOWNERS file found under root.
+// It returns no rules when the repository has none.
+func LoadCodeowners(root string) ([]Rule, error) {
+	for _, p := range CodeownersPaths {
+		f, err := os.Open(filepath.Join(root, p))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ParseCodeowners(f)
+	}
+	return nil, nil
+}
+
+// ParseCodeowners turns CODEOWNERS entries into rules whose scope is derived
+// from the first owner: @org/payments-team becomes payments-team. CODEOWNERS
+// lets the last matching line win, so the rules are returned in reverse order.
+// Lines whose pattern is invalid, such as "[z-a]", are skipped as GitHub does.
+func ParseCodeowners(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		globs := codeownersGlobs(fields[0])
+		if !validGlobs(globs) {
+			continue
+		}
+		for _, pattern := range globs {
+			rules = append(rules, Rule{Pattern: pattern, Scope: ownerScope(fields[1])})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(rules)-1; i < j; i, j = i+1, j-1 {
+		rules[i], rules[j] = rules[j], rules[i]
+	}
+	return rules, nil
+}
+
+// codeownersGlobs converts a gitignore style CODEOWNERS pattern to glob patterns
+func codeownersGlobs(pattern string) []string {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+
+	switch {
+	case strings.HasSuffix(pattern, "/"):
+		return []string{pattern + "**"}
+	case strings.HasSuffix(pattern, "*"):
+		return []string{pattern}
+	default:
+		// a bare name matches both a file and everything below a directory
+		return []string{pattern, pattern + "/**"}
+	}
+}
+
+func validGlobs(patterns []string) bool {
+	for _, p := range patterns {
+		if glob.Validate(p) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func ownerScope(owner string) string {
+	owner = strings.TrimPrefix(owner, "@")
+	if i := strings.LastIndex(owner, "/"); i >= 0 {
+		owner = owner[i+1:]
+	}
+	if i := strings.Index(owner, "@"); i >= 0 {
+		owner = owner[:i]
+	}
+	return strings.ToLower(owner)
+}
@@ -0,0 +1,134 @@
+// Package scope maps changed paths to canonical Conventional Commit scopes
+package scope
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/glob"
+)
+
+// Rule assigns Scope to every path matching Pattern
+type Rule struct {
+	Pattern string
+	Scope   string
+}
+
+// Count is the number of staged files attributed to a scope
+type Count struct {
+	Scope string
+	Files int
+}
+
+// FromMap builds rules from a glob -> scope mapping. More specific patterns,
+// i.e. those with more literal characters, are tried first.
+func FromMap(mapping map[string]string) []Rule {
+	rules := make([]Rule, 0, len(mapping))
+	for pattern, scope := range mapping {
+		rules = append(rules, Rule{Pattern: pattern, Scope: scope})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		si, sj := specificity(rules[i].Pattern), specificity(rules[j].Pattern)
+		if si != sj {
+			return si > sj
+		}
+		return rules[i].Pattern < rules[j].Pattern
+	})
+	return rules
+}
+
+func specificity(pattern string) int {
+	n := 0
+	for _, c := range pattern {
+		if !strings.ContainsRune("*?[]", c) {
+			n++
+		}
+	}
+	return n
+}
+
+// Lookup returns the scope of the first rule matching path
+func Lookup(rules []Rule, path string) (string, bool) {
+	for _, r := range rules {
+		if glob.Match(r.Pattern, path) {
+			return r.Scope, true
+		}
+	}
+	return "", false
+}
+
+// Infer attributes each file to a scope and returns the scopes ordered by the
+// number of files they cover. Files no rule matches are ignored.
+func Infer(files []string, rules []Rule) []Count {
+	counts := map[string]int{}
+	for _, f := range files {
+		if s, ok := Lookup(rules, f); ok {
+			counts[s]++
+		}
+	}
+
+	result := make([]Count, 0, len(counts))
+	for s, n := range counts {
+		result = append(result, Count{Scope: s, Files: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Files != result[j].Files {
+			return result[i].Files > result[j].Files
+		}
+		return result[i].Scope < result[j].Scope
+	})
+	return result
+}
+
+// Dominant returns the scopes sharing the highest file count
+func Dominant(counts []Count) []string {
+	var scopes []string
+	for _, c := range counts {
+		if c.Files != counts[0].Files {
+			break
+		}
+		scopes = append(scopes, c.Scope)
+	}
+	return scopes
+}
+
+// Allowed returns the sorted, de-duplicated scopes of rules plus extra
+func Allowed(rules []Rule, extra []string) []string {
+	seen := map[string]bool{}
+	var scopes []string
+	add := func(s string) {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	for _, s := range extra {
+		add(s)
+	}
+	for _, r := range rules {
+		add(r.Scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Validate checks scope against allowed. An unknown scope is replaced by the
+// single dominant scope when there is one, otherwise it is dropped. The second
+// return value reports whether the scope was changed.
+func Validate(scope string, allowed, dominant []string) (string, bool) {
+	if len(allowed) == 0 {
+		return scope, false
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, scope) {
+			return a, a != scope
+		}
+	}
+	if scope == "" {
+		return scope, false
+	}
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	return "", true
+}
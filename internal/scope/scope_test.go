@@ -0,0 +1,118 @@
+package scope
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromMapSpecificity(t *testing.T) {
+	rules := FromMap(map[string]string{
+		"internal/**":     "core",
+		"internal/llm/**": "llm",
+		"cmd/**":          "cli",
+	})
+
+	if s, _ := Lookup(rules, "internal/llm/openai/client.go"); s != "llm" {
+		t.Errorf("Lookup() = %q, want llm", s)
+	}
+	if s, _ := Lookup(rules, "internal/git/diff.go"); s != "core" {
+		t.Errorf("Lookup() = %q, want core", s)
+	}
+	if _, ok := Lookup(rules, "README.md"); ok {
+		t.Error("Lookup() matched a path no rule covers")
+	}
+}
+
+func TestInferAndDominant(t *testing.T) {
+	rules := FromMap(map[string]string{"internal/llm/**": "llm", "cmd/**": "cli"})
+	files := []string{
+		"internal/llm/openai/client.go",
+		"internal/llm/groq/client.go",
+		"cmd/commit.go",
+		"README.md",
+	}
+
+	counts := Infer(files, rules)
+	want := []Count{{Scope: "llm", Files: 2}, {Scope: "cli", Files: 1}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("Infer() = %+v, want %+v", counts, want)
+	}
+	if got := Dominant(counts); !reflect.DeepEqual(got, []string{"llm"}) {
+		t.Errorf("Dominant() = %v, want [llm]", got)
+	}
+
+	tied := Infer([]string{"cmd/a.go", "internal/llm/b.go"}, rules)
+	if got := Dominant(tied); !reflect.DeepEqual(got, []string{"cli", "llm"}) {
+		t.Errorf("Dominant() = %v, want [cli llm]", got)
+	}
+
+	if got := Dominant(nil); got != nil {
+		t.Errorf("Dominant(nil) = %v, want nil", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	allowed := []string{"cli", "llm"}
+
+	tests := []struct {
+		scope    string
+		dominant []string
+		want     string
+		changed  bool
+	}{
+		{"llm", nil, "llm", false},
+		{"LLM", nil, "llm", true},
+		{"commands", []string{"cli"}, "cli", true},
+		{"commands", []string{"cli", "llm"}, "", true},
+		{"", []string{"cli"}, "", false},
+	}
+	for _, tt := range tests {
+		got, changed := Validate(tt.scope, allowed, tt.dominant)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("Validate(%q) = %q, %v; want %q, %v", tt.scope, got, changed, tt.want, tt.changed)
+		}
+	}
+
+	if got, changed := Validate("anything", nil, nil); got != "anything" || changed {
+		t.Errorf("Validate() without allowed scopes = %q, %v", got, changed)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	rules := FromMap(map[string]string{"cmd/**": "cli", "main.go": "cli"})
+	got := Allowed(rules, []string{"docs", "cli"})
+	if !reflect.DeepEqual(got, []string{"cli", "docs"}) {
+		t.Errorf("Allowed() = %v, want [cli docs]", got)
+	}
+}
+
+func TestParseCodeowners(t *testing.T) {
+	input := `# owners
+*.md            @acme/docs-team
+/internal/llm/  @acme/ml-platform
+cmd             @octocat
+/internal/llm/ollama/ dev@example.com
+/web/[z-a]/     @acme/broken
+/pkg/[]abc].go  @acme/brackets
+`
+	rules, err := ParseCodeowners(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCodeowners() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"README.md":                     "docs-team",
+		"docs/guide.md":                 "docs-team",
+		"internal/llm/openai/client.go": "ml-platform",
+		"internal/llm/ollama/client.go": "dev",
+		"cmd/commit.go":                 "octocat",
+		"tools/cmd":                     "octocat",
+		"pkg/].go":                      "brackets",
+	}
+	for path, want := range tests {
+		if got, _ := Lookup(rules, path); got != want {
+			t.Errorf("Lookup(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
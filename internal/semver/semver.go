@@ -0,0 +1,220 @@
+// Package semver parses, compares and bumps Semantic Versions
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a Semantic Version such as 1.3.0-rc.1+build.5
+type Version struct {
+	Major, Minor, Patch int
+	// Prerelease holds the dot-separated identifiers after the hyphen
+	Prerelease []string
+	// Build is the metadata after the plus sign, ignored by comparisons
+	Build string
+}
+
+// Parse parses a version, with or without a leading v
+func Parse(s string) (Version, error) {
+	rest := strings.TrimPrefix(s, "v")
+	var v Version
+	rest, v.Build, _ = strings.Cut(rest, "+")
+	core, pre, hasPre := strings.Cut(rest, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: want MAJOR.MINOR.PATCH", s)
+	}
+	numbers := make([]int, 3)
+	for i, p := range parts {
+		n, err := parseNumber(p)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		numbers[i] = n
+	}
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+	if hasPre {
+		v.Prerelease = strings.Split(pre, ".")
+		for _, id := range v.Prerelease {
+			if id == "" || !validIdentifier(id) {
+				return Version{}, fmt.Errorf("invalid version %q: bad pre-release identifier %q", s, id)
+			}
+			if isNumeric(id) && len(id) > 1 && id[0] == '0' {
+				return Version{}, fmt.Errorf("invalid version %q: pre-release number %q has a leading zero", s, id)
+			}
+		}
+	}
+	if v.Build != "" {
+		for _, id := range strings.Split(v.Build, ".") {
+			if id == "" || !validIdentifier(id) {
+				return Version{}, fmt.Errorf("invalid version %q: bad build identifier %q", s, id)
+			}
+		}
+	}
+	return v, nil
+}
+
+func parseNumber(s string) (int, error) {
+	if s == "" || !isNumeric(s) {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("%q has a leading zero", s)
+	}
+	return strconv.Atoi(s)
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func validIdentifier(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats v without a leading v
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// IsPrerelease reports whether v has pre-release identifiers
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or 1 as a is lower than, equal to or higher than b in
+// Semantic Versioning precedence: pre-releases come before their release and
+// build metadata is ignored.
+func Compare(a, b Version) int {
+	for _, d := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if d[0] != d[1] {
+			return cmpInt(d[0], d[1])
+		}
+	}
+	switch {
+	case len(a.Prerelease) == 0 && len(b.Prerelease) == 0:
+		return 0
+	case len(a.Prerelease) == 0:
+		return 1
+	case len(b.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.Prerelease) && i < len(b.Prerelease); i++ {
+		if c := compareIdentifier(a.Prerelease[i], b.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return cmpInt(len(a.Prerelease), len(b.Prerelease))
+}
+
+// compareIdentifier orders numeric identifiers numerically and below
+// alphanumeric ones, which are ordered as strings
+func compareIdentifier(a, b string) int {
+	an, bn := isNumeric(a), isNumeric(b)
+	switch {
+	case an && bn:
+		if len(a) != len(b) {
+			return cmpInt(len(a), len(b))
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Bump is the part of a version a release increments
+type Bump int
+
+// Bumps from least to most significant
+const (
+	None Bump = iota
+	Patch
+	Minor
+	Major
+)
+
+func (b Bump) String() string {
+	switch b {
+	case Patch:
+		return "patch"
+	case Minor:
+		return "minor"
+	case Major:
+		return "major"
+	}
+	return "none"
+}
+
+// Rules adjust how versions are bumped
+type Rules struct {
+	// MajorOnZero lets breaking changes take a 0.x version to 1.0.0. By
+	// default they bump the minor, as anything may change before 1.0.0.
+	MajorOnZero bool
+}
+
+// Next returns the release after v for changes of bump b. Pre-release and
+// build metadata of v are dropped.
+func (r Rules) Next(v Version, b Bump) Version {
+	if b == Major && v.Major == 0 && !r.MajorOnZero {
+		b = Minor
+	}
+	switch b {
+	case Major:
+		return Version{Major: v.Major + 1}
+	case Minor:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	case Patch:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// Prerelease returns the next pre-release of v with identifier id, numbered
+// one past the highest of existing, e.g. 1.3.0-rc.3 after 1.3.0-rc.2
+func Prerelease(v Version, id string, existing []Version) Version {
+	n := 0
+	for _, e := range existing {
+		if e.Major != v.Major || e.Minor != v.Minor || e.Patch != v.Patch {
+			continue
+		}
+		if len(e.Prerelease) != 2 || e.Prerelease[0] != id || !isNumeric(e.Prerelease[1]) {
+			continue
+		}
+		if m, err := strconv.Atoi(e.Prerelease[1]); err == nil && m > n {
+			n = m
+		}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prerelease: []string{id, strconv.Itoa(n + 1)}}
+}
@@ -0,0 +1,94 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.2.3", want: "1.2.3"},
+		{in: "v0.10.0", want: "0.10.0"},
+		{in: "v1.3.0-rc.1+build.5", want: "1.3.0-rc.1+build.5"},
+		{in: "1.2", wantErr: true},
+		{in: "01.2.3", wantErr: true},
+		{in: "1.2.3-rc.01", wantErr: true},
+		{in: "1.2.3-", wantErr: true},
+		{in: "1.2.x", wantErr: true},
+		{in: "dev", wantErr: true},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && v.String() != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, v, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// In increasing precedence, from the Semantic Versioning specification
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "1.10.0", "2.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := Parse(ordered[i])
+			b, _ := Parse(ordered[j])
+			want := cmpInt(i, j)
+			if got := Compare(a, b); got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+	a, _ := Parse("1.0.0+linux")
+	b, _ := Parse("1.0.0+darwin")
+	if Compare(a, b) != 0 {
+		t.Errorf("build metadata must not affect precedence")
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		from  string
+		bump  Bump
+		rules Rules
+		want  string
+	}{
+		{from: "1.2.3", bump: Patch, want: "1.2.4"},
+		{from: "1.2.3", bump: Minor, want: "1.3.0"},
+		{from: "1.2.3", bump: Major, want: "2.0.0"},
+		{from: "1.2.3", bump: None, want: "1.2.3"},
+		{from: "0.4.1", bump: Major, want: "0.5.0"},
+		{from: "0.4.1", bump: Major, rules: Rules{MajorOnZero: true}, want: "1.0.0"},
+		{from: "1.3.0-rc.2", bump: Patch, want: "1.3.1"},
+	}
+	for _, tt := range tests {
+		v, _ := Parse(tt.from)
+		if got := tt.rules.Next(v, tt.bump).String(); got != tt.want {
+			t.Errorf("Next(%s, %s) = %s, want %s", tt.from, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestPrerelease(t *testing.T) {
+	var existing []Version
+	for _, s := range []string{"1.3.0-rc.1", "1.3.0-rc.2", "1.3.0-beta.7", "1.2.0-rc.9"} {
+		v, _ := Parse(s)
+		existing = append(existing, v)
+	}
+	v, _ := Parse("1.3.0")
+	if got := Prerelease(v, "rc", existing).String(); got != "1.3.0-rc.3" {
+		t.Errorf("Prerelease(rc) = %s, want 1.3.0-rc.3", got)
+	}
+	if got := Prerelease(v, "alpha", existing).String(); got != "1.3.0-alpha.1" {
+		t.Errorf("Prerelease(alpha) = %s, want 1.3.0-alpha.1", got)
+	}
+}
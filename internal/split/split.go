@@ -0,0 +1,106 @@
+// Package split groups the hunks of a staged diff into separate commits
+package split
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rshdhere/vibecheck/internal/patch"
+)
+
+// SystemPrompt replaces the commit message instructions of the providers
+const SystemPrompt = `You are a senior software engineer splitting a mixed set of staged changes into atomic commits.
+Given numbered hunks of a git diff, group them into logically separate changes, such as a bug fix, a refactoring and a documentation update, and write a Conventional Commit message for each group.
+
+Put every hunk in exactly one group. Keep hunks that depend on each other, such as a new function and its callers or a change and its tests, in the same group.
+Order the groups so that each commit builds on the ones before it. Use as few groups as the changes need; a single group is fine when everything belongs together.
+
+Respond with a JSON array only, without a code block or commentary:
+[{"message": "fix(parser): handle empty input", "hunks": [1, 3]}, {"message": "docs: describe the parser", "hunks": [2]}]
+A message may have a body after a blank line, written as \n\n in the JSON string.
+
+The user's extra context is in the next message and the numbered hunks in the one after.`
+
+// Group is one proposed commit: its message and the IDs of its hunks
+type Group struct {
+	Message string `json:"message"`
+	Hunks   []int  `json:"hunks"`
+}
+
+// Describe lists hunks for the provider, numbered by ID. The content of hunks
+// for which omit returns true, such as lockfiles, is replaced by a line count.
+func Describe(hunks []patch.Hunk, omit func(path string) bool) string {
+	var b strings.Builder
+	for _, h := range hunks {
+		fmt.Fprintf(&b, "Hunk %d: %s\n", h.ID, h.Path)
+		switch {
+		case h.Binary:
+			b.WriteString("binary file changed\n")
+		case omit != nil && omit(h.Path):
+			fmt.Fprintf(&b, "%d lines added, %d deleted, content omitted\n", h.Added, h.Deleted)
+		case h.Text == "":
+			b.WriteString(h.Header)
+		default:
+			b.WriteString(h.Text)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Parse reads the groups from a provider response for hunks numbered 1 to n.
+// Unknown and repeated hunk IDs are dropped, and hunks the response leaves
+// out join the group of the hunk before them, or the first group.
+func Parse(response string, n int) ([]Group, error) {
+	start, end := strings.Index(response, "["), strings.LastIndex(response, "]")
+	if start < 0 || end < start {
+		return nil, errors.New("the provider did not answer with a JSON array of commits")
+	}
+	var proposed []Group
+	if err := json.Unmarshal([]byte(response[start:end+1]), &proposed); err != nil {
+		return nil, fmt.Errorf("read the proposed commits: %w", err)
+	}
+
+	owner := make(map[int]int, n)
+	var groups []Group
+	for _, g := range proposed {
+		message := strings.TrimSpace(g.Message)
+		var ids []int
+		for _, id := range g.Hunks {
+			if _, taken := owner[id]; id < 1 || id > n || taken {
+				continue
+			}
+			owner[id] = len(groups)
+			ids = append(ids, id)
+		}
+		if message == "" || len(ids) == 0 {
+			for _, id := range ids {
+				delete(owner, id)
+			}
+			continue
+		}
+		groups = append(groups, Group{Message: message, Hunks: ids})
+	}
+	if len(groups) == 0 {
+		return nil, errors.New("the provider proposed no commits")
+	}
+
+	for id := 1; id <= n; id++ {
+		if _, ok := owner[id]; ok {
+			continue
+		}
+		g := 0
+		if prev, ok := owner[id-1]; ok {
+			g = prev
+		}
+		owner[id] = g
+		groups[g].Hunks = append(groups[g].Hunks, id)
+	}
+	for _, g := range groups {
+		sort.Ints(g.Hunks)
+	}
+	return groups, nil
+}
@@ -0,0 +1,50 @@
+package split
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rshdhere/vibecheck/internal/patch"
+)
+
+func TestDescribe(t *testing.T) {
+	hunks := []patch.Hunk{
+		{ID: 1, Path: "main.go", Header: "diff --git a/main.go b/main.go\n", Text: "@@ -1 +1 @@\n-a\n+b\n"},
+		{ID: 2, Path: "go.sum", Header: "diff --git a/go.sum b/go.sum\n", Text: "@@ -1 +1 @@\n-x\n+y\n", Added: 1, Deleted: 1},
+		{ID: 3, Path: "logo.png", Header: "diff --git a/logo.png b/logo.png\nGIT binary patch\n", Binary: true},
+	}
+	got := Describe(hunks, func(path string) bool { return path == "go.sum" })
+	want := "Hunk 1: main.go\n@@ -1 +1 @@\n-a\n+b\n\n" +
+		"Hunk 2: go.sum\n1 lines added, 1 deleted, content omitted\n\n" +
+		"Hunk 3: logo.png\nbinary file changed\n\n"
+	if got != want {
+		t.Errorf("Describe() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	response := "Here you go:\n```json\n" + `[
+  {"message": "fix: handle empty input", "hunks": [1, 3, 9]},
+  {"message": "", "hunks": [2]},
+  {"message": "docs: describe the parser\n\nWith an example.", "hunks": [3, 5]}
+]` + "\n```"
+	groups, err := Parse(response, 5)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []Group{
+		// 2 and 4 were left out and join the group of the hunk before them
+		{Message: "fix: handle empty input", Hunks: []int{1, 2, 3, 4}},
+		{Message: "docs: describe the parser\n\nWith an example.", Hunks: []int{5}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("Parse() = %+v, want %+v", groups, want)
+	}
+
+	for _, bad := range []string{"fix: everything", `[{"message": "fix: x", "hunks": [7]}]`, `[{"message": 1}]`} {
+		if _, err := Parse(bad, 2); err == nil || strings.Contains(err.Error(), "%!") {
+			t.Errorf("Parse(%q) error = %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package structure
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// goParser reads top-level functions, methods and types with go/parser
+type goParser struct{}
+
+func (goParser) Symbols(src string) (map[Symbol]string, error) {
+	out := map[Symbol]string{}
+	if strings.TrimSpace(src) == "" {
+		return out, nil
+	}
+	fset := token.NewFileSet()
+	// Without parser.ParseComments the printed declarations carry no comments
+	file, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	format := func(node any) (string, error) {
+		var b bytes.Buffer
+		if err := printer.Fprint(&b, fset, node); err != nil {
+			return "", err
+		}
+		// Removed comments leave blank lines behind, which carry no meaning
+		var lines []string
+		for _, line := range strings.Split(b.String(), "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			sym := Symbol{Kind: KindFunc, Name: d.Name.Name}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				sym = Symbol{Kind: KindMethod, Name: receiverName(d.Recv.List[0].Type) + "." + d.Name.Name}
+			}
+			text, err := format(d)
+			if err != nil {
+				return nil, err
+			}
+			out[sym] = text
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				text, err := format(ts)
+				if err != nil {
+					return nil, err
+				}
+				out[Symbol{Kind: KindType, Name: ts.Name.Name}] = text
+			}
+		}
+	}
+	return out, nil
+}
+
+// receiverName strips pointers and type parameters from a receiver type
+func receiverName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return "?"
+		}
+	}
+}
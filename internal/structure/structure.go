@@ -0,0 +1,122 @@
+// Package structure summarizes a change by the declarations it touches, such
+// as the functions, types and methods added, removed or modified in a file
+package structure
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of a declaration
+type Kind string
+
+const (
+	KindFunc   Kind = "func"
+	KindMethod Kind = "method"
+	KindType   Kind = "type"
+)
+
+// Symbol identifies a declaration within a file. Methods are named after
+// their receiver type, e.g. "Matcher.Match".
+type Symbol struct {
+	Kind Kind
+	Name string
+}
+
+// Op is what happened to a symbol
+type Op string
+
+const (
+	OpAdded    Op = "added"
+	OpRemoved  Op = "removed"
+	OpModified Op = "modified"
+)
+
+// Change is one symbol that differs between two versions of a file
+type Change struct {
+	File string
+	Op   Op
+	Symbol
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s %s %s", c.File, c.Op, c.Kind, c.Name)
+}
+
+// Parser reads the declarations of one language. Symbols maps each symbol to
+// a normalized form of its source, so that two versions can be compared
+// without formatting or comment changes counting as modifications.
+type Parser interface {
+	Symbols(src string) (map[Symbol]string, error)
+}
+
+var parsers = map[string]Parser{}
+
+// Register makes a parser available for files with the extension ext, e.g. ".go"
+func Register(ext string, p Parser) {
+	parsers[ext] = p
+}
+
+func init() {
+	Register(".go", goParser{})
+}
+
+// Supported reports whether a parser is registered for file
+func Supported(file string) bool {
+	_, ok := parsers[path.Ext(file)]
+	return ok
+}
+
+// Compare lists the symbols that differ between two versions of file. Either
+// side may be empty when the file is added or deleted. Files without a
+// registered parser have no changes.
+func Compare(file, before, after string) ([]Change, error) {
+	p, ok := parsers[path.Ext(file)]
+	if !ok {
+		return nil, nil
+	}
+	old, err := p.Symbols(before)
+	if err != nil {
+		return nil, fmt.Errorf("parse old %s: %w", file, err)
+	}
+	cur, err := p.Symbols(after)
+	if err != nil {
+		return nil, fmt.Errorf("parse new %s: %w", file, err)
+	}
+
+	var changes []Change
+	for sym, src := range cur {
+		prev, existed := old[sym]
+		switch {
+		case !existed:
+			changes = append(changes, Change{File: file, Op: OpAdded, Symbol: sym})
+		case prev != src:
+			changes = append(changes, Change{File: file, Op: OpModified, Symbol: sym})
+		}
+	}
+	for sym := range old {
+		if _, ok := cur[sym]; !ok {
+			changes = append(changes, Change{File: file, Op: OpRemoved, Symbol: sym})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Op != changes[j].Op {
+			return opOrder[changes[i].Op] < opOrder[changes[j].Op]
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, nil
+}
+
+var opOrder = map[Op]int{OpAdded: 0, OpModified: 1, OpRemoved: 2}
+
+// Summary lists changes one per line in the given order
+func Summary(changes []Change) string {
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		lines = append(lines, "- "+c.String())
+	}
+	return strings.Join(lines, "\n")
+}
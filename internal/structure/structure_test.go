@@ -0,0 +1,109 @@
+package structure
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareGo(t *testing.T) {
+	before := `package store
+
+// Store keeps items
+type Store struct {
+	items map[string]string
+}
+
+func New() *Store {
+	return &Store{items: map[string]string{}}
+}
+
+func (s *Store) Get(key string) string {
+	return s.items[key]
+}
+
+func (s *Store) Delete(key string) {
+	delete(s.items, key)
+}
+`
+	after := `package store
+
+// Store keeps items in memory
+type Store struct {
+	items map[string]string
+}
+
+func New() *Store {
+	// start empty
+	return &Store{items:   map[string]string{}}
+}
+
+func (s *Store) Get(key string) (string, bool) {
+	v, ok := s.items[key]
+	return v, ok
+}
+
+func (s *Store) Len() int { return len(s.items) }
+
+type Option func(*Store)
+`
+	got, err := Compare("store/store.go", before, after)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	want := []Change{
+		{File: "store/store.go", Op: OpAdded, Symbol: Symbol{KindType, "Option"}},
+		{File: "store/store.go", Op: OpAdded, Symbol: Symbol{KindMethod, "Store.Len"}},
+		{File: "store/store.go", Op: OpModified, Symbol: Symbol{KindMethod, "Store.Get"}},
+		{File: "store/store.go", Op: OpRemoved, Symbol: Symbol{KindMethod, "Store.Delete"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() = %+v, want %+v", got, want)
+	}
+	if s := got[2].String(); s != "store/store.go: modified method Store.Get" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestCompareNewAndDeletedFiles(t *testing.T) {
+	src := "package main\n\nfunc main() {}\n"
+	got, err := Compare("main.go", "", src)
+	if err != nil || len(got) != 1 || got[0].Op != OpAdded {
+		t.Errorf("Compare(new file) = %+v, %v", got, err)
+	}
+	got, err = Compare("main.go", src, "")
+	if err != nil || len(got) != 1 || got[0].Op != OpRemoved {
+		t.Errorf("Compare(deleted file) = %+v, %v", got, err)
+	}
+}
+
+func TestCompareUnsupported(t *testing.T) {
+	if Supported("app.py") {
+		t.Error("Supported(app.py) = true")
+	}
+	got, err := Compare("app.py", "a = 1", "a = 2")
+	if err != nil || got != nil {
+		t.Errorf("Compare(app.py) = %+v, %v", got, err)
+	}
+}
+
+type lineParser struct{}
+
+func (lineParser) Symbols(src string) (map[Symbol]string, error) {
+	return map[Symbol]string{{Kind: KindFunc, Name: src}: src}, nil
+}
+
+func TestRegister(t *testing.T) {
+	Register(".test", lineParser{})
+	defer delete(parsers, ".test")
+
+	got, err := Compare("x.test", "a", "b")
+	if err != nil || len(got) != 2 {
+		t.Errorf("Compare(x.test) = %+v, %v", got, err)
+	}
+}
+
+func TestCompareSyntaxError(t *testing.T) {
+	if _, err := Compare("main.go", "package main\n", "package main\nfunc {"); err == nil {
+		t.Error("Compare() error = nil, want parse error")
+	}
+}
@@ -2,11 +2,14 @@ package notify
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
 )
 
 // ShowStageReminder displays a minimal bubbletea notification letting the user
@@ -16,6 +19,7 @@ func ShowStageReminder() {
 		title:       "NO STAGED CHANGES DETECTED !!",
 		description: "Use `git add <files>` to stage your changes, then rerun `vibecheck commit`.",
 		hint:        "Press any key (or wait a second) to continue.",
+		dismissHint: true,
 	}
 
 	runProgram(m, "NO STAGED CHANGES DETECTED !!. Please stage files and rerun `vibecheck commit`.")
@@ -51,19 +55,47 @@ func ShowMissingModel(providerName, model string) {
 	runProgram(m, fallback)
 }
 
+var (
+	// plain is forced by SetPlain for scripted runs
+	plain bool
+	// stderr receives plain notifications
+	stderr io.Writer = os.Stderr
+)
+
+// SetPlain turns notifications into plain stderr messages even on a terminal,
+// for runs that must never wait on the user
+func SetPlain(on bool) {
+	plain = on
+}
+
 func runProgram(m messageModel, fallback string) {
+	// Scripts and CI have no terminal to draw on and nobody to press a key
+	if plain || !term.IsTerminal(int(os.Stdout.Fd())) || !term.IsTerminal(int(os.Stdin.Fd())) {
+		writePlain(stderr, m)
+		return
+	}
 	p := tea.NewProgram(m, tea.WithoutSignalHandler())
 	if _, err := p.Run(); err != nil {
 		fmt.Println(fallback)
 	}
 }
 
+// writePlain prints m without styling, e.g. "vibecheck: NO STAGED CHANGES DETECTED !!"
+func writePlain(w io.Writer, m messageModel) {
+	fmt.Fprintf(w, "vibecheck: %s\n  %s\n", m.title, m.description)
+	if !m.dismissHint {
+		fmt.Fprintf(w, "  %s\n", m.hint)
+	}
+}
+
 type messageModel struct {
 	width       int
 	height      int
 	title       string
 	description string
 	hint        string
+	// dismissHint marks a hint that only says how to close the message
+	dismissHint bool
 }
 
 type autoCloseMsg struct{}
@@ -1,6 +1,7 @@
 package notify
 
 import (
+	"bytes"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -205,3 +206,17 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestWritePlain(t *testing.T) {
+	var b bytes.Buffer
+	writePlain(&b, messageModel{title: "TITLE", description: "Description.", hint: "Hint."})
+	if want := "vibecheck: TITLE\n  Description.\n  Hint.\n"; b.String() != want {
+		t.Errorf("writePlain() = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	writePlain(&b, messageModel{title: "TITLE", description: "Description.", hint: "Press any key.", dismissHint: true})
+	if want := "vibecheck: TITLE\n  Description.\n"; b.String() != want {
+		t.Errorf("writePlain(dismiss hint) = %q, want %q", b.String(), want)
+	}
+}